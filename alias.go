@@ -0,0 +1,177 @@
+package formmap
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// mapAliases maps each aliased document path into its form path. Document
+// paths use the same names as error keys and may index slices and maps; form
+// paths are dot-separated Go field names from the form root.
+func (m *Mapper) mapAliases(docVal, formVal reflect.Value, st *mapState) error {
+	for _, docPath := range slices.Sorted(maps.Keys(st.fieldAliases)) {
+		formPath := st.fieldAliases[docPath]
+		if matchAnyPath(st.skipFields, docPath) || !selectsPath(st.onlyFields, docPath) {
+			continue
+		}
+
+		docParent, docFieldVal, fc, ok := m.lookupDocPath(docVal, docPath)
+		if !ok {
+			continue
+		}
+
+		formFieldVal, formField, err := lookupFormPath(formVal, formPath)
+		if err != nil {
+			return fmt.Errorf("%w: alias %s: %w", ErrInvalidOption, docPath, err)
+		}
+
+		path := pathOf(docPath)
+		err = m.withFieldHooks(path, docFieldVal, formFieldVal, func() error {
+			if handled, err := m.applyFieldMapper(st, docParent, docFieldVal, formFieldVal, path); handled {
+				return err
+			}
+			if err := m.mapField(docParent, docFieldVal, formFieldVal, st, path, fc, formField); err != nil {
+				return fieldError(docPath, ErrMappingFailed, err)
+			}
+			return nil
+		})
+		if err != nil {
+			if err := st.fail(err); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// lookupDocPath follows docPath through nested structs, string-keyed maps and
+// indexed segments such as "Items[3]" or "Prices[usd]". parent is the struct
+// or map holding the last named segment. ok is false when a segment doesn't
+// exist or a pointer on the way is nil.
+func (m *Mapper) lookupDocPath(docVal reflect.Value, docPath string) (parent, field reflect.Value, fc FieldConfig, ok bool) {
+	prefix := ""
+	for _, seg := range SplitPath(docPath) {
+		for docVal.Kind() == reflect.Ptr {
+			if docVal.IsNil() {
+				return parent, field, fc, false
+			}
+			docVal = docVal.Elem()
+		}
+		if docVal.Kind() == reflect.Interface && !docVal.IsNil() {
+			docVal = docVal.Elem()
+		}
+
+		if strings.HasPrefix(seg, "[") {
+			field, ok = indexDocValue(docVal, seg[1:len(seg)-1])
+			if !ok {
+				return parent, field, fc, false
+			}
+			prefix += seg
+			fc = m.elemConfig(fc, pathOf(prefix))
+			docVal = field
+			continue
+		}
+
+		fieldPath := seg
+		if prefix != "" {
+			fieldPath = prefix + "." + seg
+		}
+		if docVal.Kind() == reflect.Map && docVal.Type().Key().Kind() == reflect.String {
+			field = docVal.MapIndex(reflect.ValueOf(seg).Convert(docVal.Type().Key()))
+			if !field.IsValid() {
+				return parent, field, fc, false
+			}
+			fc, _ = m.fieldConfig(pathOf(fieldPath))
+			parent, prefix, docVal = docVal, fieldPath, field
+			continue
+		}
+		if docVal.Kind() != reflect.Struct {
+			return parent, field, fc, false
+		}
+
+		found := false
+		for i := 0; i < docVal.NumField(); i++ {
+			sf := docVal.Type().Field(i)
+			if !sf.IsExported() || m.getFieldName(sf) != seg {
+				continue
+			}
+			fieldPath, _, fieldFC, _ := m.resolveField(sf, prefix)
+			parent, field, fc, prefix = docVal, docVal.Field(i), fieldFC, fieldPath
+			found = true
+			break
+		}
+		if !found {
+			return parent, field, fc, false
+		}
+		docVal = field
+	}
+	return parent, field, fc, true
+}
+
+// indexDocValue returns the slice or array element at index key, or the map
+// entry for key when the map has string or integer keys.
+func indexDocValue(v reflect.Value, key string) (reflect.Value, bool) {
+	switch {
+	case isList(v):
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i >= v.Len() {
+			return reflect.Value{}, false
+		}
+		return v.Index(i), true
+	case v.Kind() == reflect.Map:
+		keyType := v.Type().Key()
+		var k reflect.Value
+		switch keyType.Kind() {
+		case reflect.String:
+			k = reflect.ValueOf(key).Convert(keyType)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n, err := strconv.ParseInt(key, 10, keyType.Bits())
+			if err != nil {
+				return reflect.Value{}, false
+			}
+			k = reflect.New(keyType).Elem()
+			k.SetInt(n)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			n, err := strconv.ParseUint(key, 10, keyType.Bits())
+			if err != nil {
+				return reflect.Value{}, false
+			}
+			k = reflect.New(keyType).Elem()
+			k.SetUint(n)
+		default:
+			return reflect.Value{}, false
+		}
+		elem := v.MapIndex(k)
+		return elem, elem.IsValid()
+	}
+	return reflect.Value{}, false
+}
+
+// lookupFormPath follows formPath by Go field names, allocating nil pointers
+// on the way.
+func lookupFormPath(formVal reflect.Value, formPath string) (reflect.Value, reflect.StructField, error) {
+	var sf reflect.StructField
+	for _, name := range strings.Split(formPath, ".") {
+		for formVal.Kind() == reflect.Ptr {
+			if formVal.IsNil() {
+				formVal.Set(reflect.New(formVal.Type().Elem()))
+			}
+			formVal = formVal.Elem()
+		}
+		if formVal.Kind() != reflect.Struct {
+			return reflect.Value{}, sf, fmt.Errorf("form path %s: %s is not a struct", formPath, formVal.Type())
+		}
+
+		var ok bool
+		sf, ok = formVal.Type().FieldByName(name)
+		if !ok || !sf.IsExported() {
+			return reflect.Value{}, sf, fmt.Errorf("form path %s: no field %s", formPath, name)
+		}
+		formVal = formVal.FieldByIndex(sf.Index)
+	}
+	return formVal, sf, nil
+}
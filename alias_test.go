@@ -0,0 +1,61 @@
+package formmap
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMapper_FieldAliases(t *testing.T) {
+	type metadata struct {
+		Author string
+		Tags   []string
+	}
+	type post struct {
+		Title    string
+		Slug     string
+		Metadata *metadata
+	}
+	type postForm struct {
+		Title      FormInputData
+		URL        FormInputData
+		AuthorName FormInputData
+		Extra      struct{ Tags FormInputData }
+	}
+
+	d := &post{Title: "Hello", Slug: "hello", Metadata: &metadata{Author: "Ada", Tags: []string{"go"}}}
+	valErr := &ValidationError{Errors: Errors{"Metadata.Author": ValidationField{Tag: "required"}}}
+
+	f := &postForm{}
+	err := NewMapper().MapToFormWithOptions(d, valErr, f, MapOptions{
+		FieldAliases: map[string]string{
+			"Slug":            "URL",
+			"Metadata.Author": "AuthorName",
+			"Metadata.Tags":   "Extra.Tags",
+		},
+	})
+	if err != nil {
+		t.Fatalf("MapToFormWithOptions() error = %v", err)
+	}
+
+	if f.Title.Value != "Hello" || f.URL.Value != "hello" {
+		t.Errorf("Title/URL = %q/%q", f.Title.Value, f.URL.Value)
+	}
+	if f.AuthorName.Value != "Ada" || f.AuthorName.Error != "This field is required" {
+		t.Errorf("AuthorName = %+v, want value and error from Metadata.Author", f.AuthorName)
+	}
+	if f.Extra.Tags.Value != "go" {
+		t.Errorf("Extra.Tags = %q, want go", f.Extra.Tags.Value)
+	}
+
+	// A nil pointer on the document side leaves the target alone.
+	f = &postForm{}
+	err = NewMapper().MapToFormWithOptions(&post{}, nil, f, MapOptions{FieldAliases: map[string]string{"Metadata.Author": "AuthorName"}})
+	if err != nil || f.AuthorName.Value != "" {
+		t.Errorf("nil parent: err = %v, AuthorName = %q", err, f.AuthorName.Value)
+	}
+
+	err = NewMapper().MapToFormWithOptions(d, nil, &postForm{}, MapOptions{FieldAliases: map[string]string{"Slug": "Missing"}})
+	if !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("unknown form path: error = %v, want ErrInvalidOption", err)
+	}
+}
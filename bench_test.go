@@ -0,0 +1,207 @@
+package formmap
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type benchItem struct {
+	SKU      string
+	Price    float64
+	Quantity int
+	Weight   float32
+	Active   bool
+}
+
+type benchDoc struct {
+	Name      string
+	Price     float64
+	Quantity  int64
+	Rating    float32
+	Active    bool
+	CreatedAt time.Time
+	Shelf     time.Duration
+	Tags      []string
+	Items     []benchItem
+}
+
+type benchItemForm struct {
+	SKU      FormInputData
+	Price    FormInputData
+	Quantity FormInputData
+	Weight   FormInputData
+	Active   FormInputData
+}
+
+type benchForm struct {
+	Name      FormInputData
+	Price     FormInputData
+	Quantity  FormInputData
+	Rating    FormInputData
+	Active    FormInputData
+	CreatedAt FormInputData
+	Shelf     FormInputData
+	Tags      FormInputData
+	Items     []benchItemForm
+}
+
+func newBenchDoc(items int) *benchDoc {
+	d := &benchDoc{
+		Name:      "Desk lamp",
+		Price:     1234.56,
+		Quantity:  1200,
+		Rating:    4.5,
+		Active:    true,
+		CreatedAt: time.Date(2024, 3, 9, 10, 0, 0, 0, time.UTC),
+		Shelf:     90 * time.Minute,
+		Tags:      []string{"home", "office", "light"},
+	}
+	for i := range items {
+		d.Items = append(d.Items, benchItem{SKU: "SKU-" + strconv.Itoa(i), Price: float64(i) + 0.99, Quantity: i * 1000, Weight: 1.25, Active: i%2 == 0})
+	}
+	return d
+}
+
+func benchmarkMapToForm(b *testing.B, m *Mapper, items int) {
+	doc := newBenchDoc(items)
+	valErr := &ValidationError{Errors: Errors{"Items[1].Price": ValidationField{Tag: "required"}}}
+	form := &benchForm{}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for b.Loop() {
+		if err := m.MapToForm(doc, valErr, form); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMapToForm_Small(b *testing.B) {
+	benchmarkMapToForm(b, NewMapper(), 0)
+}
+
+func BenchmarkMapToForm_Large(b *testing.B) {
+	benchmarkMapToForm(b, NewMapper(), 500)
+}
+
+func BenchmarkMapToForm_LargeLocale(b *testing.B) {
+	benchmarkMapToForm(b, NewMapper(WithLocale("de-DE")), 500)
+}
+
+func BenchmarkPlan_Execute_Small(b *testing.B) {
+	m := NewMapper()
+	plan, err := m.Compile(reflect.TypeOf(benchDoc{}), reflect.TypeOf(benchForm{}))
+	if err != nil {
+		b.Fatal(err)
+	}
+	doc := newBenchDoc(0)
+	valErr := &ValidationError{Errors: Errors{"Items[1].Price": ValidationField{Tag: "required"}}}
+	form := &benchForm{}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for b.Loop() {
+		if err := plan.Execute(doc, valErr, form); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMapFromForm_Large(b *testing.B) {
+	m := NewMapper()
+	form := &benchForm{}
+	if err := m.MapToForm(newBenchDoc(500), nil, form); err != nil {
+		b.Fatal(err)
+	}
+	doc := &benchDoc{}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for b.Loop() {
+		if err := m.MapFromForm(form, doc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkConvertValue(b *testing.B) {
+	m := NewMapper()
+	st := &mapState{}
+	loc, _ := LookupLocale("de-DE")
+	localized := &mapState{locale: &loc}
+
+	for _, bc := range []struct {
+		name string
+		st   *mapState
+		v    any
+	}{
+		{"int", st, 123456},
+		{"uint16", st, uint16(6553)},
+		{"float64", st, 1234.5678},
+		{"float64Locale", localized, 1234567.5678},
+		{"int32Locale", localized, int32(-1234567)},
+	} {
+		v := reflect.ValueOf(bc.v)
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := m.convertFieldValue(bc.st, v, v); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkMapToForm_Maps(b *testing.B) {
+	type doc struct {
+		Stock map[string]benchItem
+		Notes map[int]string
+	}
+	type form struct {
+		Stock map[string]benchItemForm
+		Notes map[int]FormInputData
+	}
+
+	d := &doc{Stock: make(map[string]benchItem), Notes: make(map[int]string)}
+	for i := range 200 {
+		d.Stock["SKU-"+strconv.Itoa(i)] = benchItem{SKU: "SKU-" + strconv.Itoa(i), Price: 9.99}
+		d.Notes[i] = "note"
+	}
+
+	m := NewMapper()
+	f := &form{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for b.Loop() {
+		if err := m.MapToForm(d, nil, f); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkFieldPath compares building element paths with fmt against
+// extending a pathKey, as mapping does for every field.
+func BenchmarkFieldPath(b *testing.B) {
+	b.Run("sprintf", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			for i := range 100 {
+				_ = fmt.Sprintf("%s[%d]", "Items", i) + "." + "Price"
+			}
+		}
+	})
+	b.Run("pathKey", func(b *testing.B) {
+		var buf []byte
+		b.ReportAllocs()
+		for b.Loop() {
+			items := rootPath(&buf).field("Items")
+			for i := range 100 {
+				_ = items.index(i).field("Price")
+			}
+		}
+	})
+}
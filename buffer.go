@@ -0,0 +1,49 @@
+package formmap
+
+import (
+	"strconv"
+	"sync"
+)
+
+// bufPool holds scratch buffers for localizing numbers, whose length depends
+// on the locale's separators.
+var bufPool = sync.Pool{
+	New: func() any {
+		b := make([]byte, 0, 64)
+		return &b
+	},
+}
+
+func (st *mapState) formatFloat(f float64, bitSize int) string {
+	var scratch [32]byte
+	return st.localize(strconv.AppendFloat(scratch[:0], f, 'f', -1, bitSize))
+}
+
+// localize returns num, a plain number, in the call's locale.
+func (st *mapState) localize(num []byte) string {
+	if st == nil || st.locale == nil {
+		return string(num)
+	}
+	return localized(*st.locale, num)
+}
+
+func (st *mapState) formatNumber(s string) string {
+	if st == nil || st.locale == nil {
+		return s
+	}
+	return localized(*st.locale, s)
+}
+
+// localized formats num with l's separators in a pooled buffer, so only the
+// result is allocated.
+func localized[S ~string | ~[]byte](l Locale, num S) string {
+	bp := bufPool.Get().(*[]byte)
+	defer bufPool.Put(bp)
+
+	b, ok := appendNumber((*bp)[:0], l, num)
+	*bp = b[:0]
+	if !ok {
+		return string(num)
+	}
+	return string(b)
+}
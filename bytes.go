@@ -0,0 +1,81 @@
+package formmap
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+)
+
+// WithBytesEncoding sets how []byte fields are written as a single form
+// value: "base64" (the default), "base64url" or "hex".
+func WithBytesEncoding(encoding string) MapperOption {
+	return func(m *Mapper) {
+		m.bytesEncoding = encoding
+	}
+}
+
+func validBytesEncoding(encoding string) bool {
+	switch encoding {
+	case "", "base64", "base64url", "hex":
+		return true
+	}
+	return false
+}
+
+func isBytes(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+}
+
+func (m *Mapper) encodeBytes(b []byte) string {
+	switch m.bytesEncoding {
+	case "hex":
+		return hex.EncodeToString(b)
+	case "base64url":
+		return base64.URLEncoding.EncodeToString(b)
+	default:
+		return base64.StdEncoding.EncodeToString(b)
+	}
+}
+
+func (m *Mapper) decodeBytes(s string) ([]byte, error) {
+	switch m.bytesEncoding {
+	case "hex":
+		return hex.DecodeString(s)
+	case "base64url":
+		return base64.URLEncoding.DecodeString(s)
+	default:
+		return base64.StdEncoding.DecodeString(s)
+	}
+}
+
+// RegisterHexType renders a fixed-size byte array type, such as MongoDB's
+// primitive.ObjectID, as lowercase hex and parses hex back into it:
+//
+//	formmap.RegisterHexType[primitive.ObjectID](mapper)
+//
+// It panics if T is not a byte array.
+func RegisterHexType[T any](m *Mapper) {
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Array || t.Elem().Kind() != reflect.Uint8 {
+		panic(fmt.Sprintf("formmap: RegisterHexType: %s is not a byte array", t))
+	}
+
+	m.RegisterConverter(t, func(v reflect.Value) string {
+		b := make([]byte, v.Len())
+		reflect.Copy(reflect.ValueOf(b), v)
+		return hex.EncodeToString(b)
+	})
+
+	m.RegisterParser(t, func(s string, v reflect.Value) error {
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", t, err)
+		}
+		if len(b) != v.Len() {
+			return fmt.Errorf("invalid %s: want %d bytes, got %d", t, v.Len(), len(b))
+		}
+		reflect.Copy(v, reflect.ValueOf(b))
+		return nil
+	})
+}
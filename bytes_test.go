@@ -0,0 +1,107 @@
+package formmap
+
+import (
+	"testing"
+)
+
+// testObjectID mirrors MongoDB's primitive.ObjectID, whose String method
+// returns ObjectID("...") rather than the bare hex.
+type testObjectID [12]byte
+
+func (id testObjectID) String() string { return `ObjectID("...")` }
+
+func TestRegisterHexType(t *testing.T) {
+	type post struct {
+		ID     testObjectID
+		Author *testObjectID
+	}
+	type postForm struct {
+		ID     FormInputData
+		Author FormInputData
+	}
+
+	mapper := NewMapper()
+	RegisterHexType[testObjectID](mapper)
+
+	id := testObjectID{0x50, 0x7f, 0x1f, 0x77, 0xbc, 0xf8, 0x6c, 0xd7, 0x99, 0x43, 0x90, 0x11}
+	f := &postForm{}
+	if err := mapper.MapToForm(&post{ID: id, Author: &id}, nil, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+	if f.ID.Value != "507f1f77bcf86cd799439011" || f.Author.Value != f.ID.Value {
+		t.Errorf("MapToForm() = %+v", f)
+	}
+
+	back := &post{}
+	if err := mapper.MapFromForm(f, back); err != nil {
+		t.Fatalf("MapFromForm() error = %v", err)
+	}
+	if back.ID != id || *back.Author != id {
+		t.Errorf("MapFromForm() = %+v", back)
+	}
+
+	for _, bad := range []string{"zz", "507f1f77"} {
+		f.ID.Value = bad
+		if err := mapper.MapFromForm(f, back); err == nil {
+			t.Errorf("MapFromForm(%q) expected error", bad)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for non byte array type")
+		}
+	}()
+	RegisterHexType[string](mapper)
+}
+
+func TestMapper_BytesEncoding(t *testing.T) {
+	type file struct {
+		Data []byte
+		Hash []byte
+	}
+	type fileForm struct {
+		Data FormInputData
+		Hash FormInputData
+	}
+
+	d := &file{Data: []byte{0xfb, 0xff, 0x01}}
+
+	tests := []struct {
+		name string
+		opts []MapperOption
+		want string
+	}{
+		{"base64 by default", nil, "+/8B"},
+		{"base64url", []MapperOption{WithBytesEncoding("base64url")}, "-_8B"},
+		{"hex", []MapperOption{WithBytesEncoding("hex")}, "fbff01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapper := NewMapper(tt.opts...)
+			f := &fileForm{}
+			if err := mapper.MapToForm(d, nil, f); err != nil {
+				t.Fatalf("MapToForm() error = %v", err)
+			}
+			if f.Data.Value != tt.want {
+				t.Errorf("Data = %q, want %q", f.Data.Value, tt.want)
+			}
+			if f.Hash.Value != "" {
+				t.Errorf("Hash = %q, want empty", f.Hash.Value)
+			}
+
+			back := &file{}
+			if err := mapper.MapFromForm(f, back); err != nil {
+				t.Fatalf("MapFromForm() error = %v", err)
+			}
+			if string(back.Data) != string(d.Data) || back.Hash != nil {
+				t.Errorf("MapFromForm() = %+v, want %+v", back, d)
+			}
+		})
+	}
+
+	if err := NewMapper(WithBytesEncoding("base32")).MapToForm(d, nil, &fileForm{}); err == nil {
+		t.Error("expected error for unknown bytes encoding")
+	}
+}
@@ -0,0 +1,681 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const formmapPath = "github.com/omareloui/formmap"
+
+// pair names a document type and the form type it maps into.
+type pair struct {
+	Doc, Form string
+}
+
+// typeExpr is a type expression together with the file it appears in, so
+// package selectors resolve against that file's imports.
+type typeExpr struct {
+	expr ast.Expr
+	file *ast.File
+}
+
+type generator struct {
+	pkg       string
+	types     map[string]typeExpr
+	methods   map[string][]string
+	jsonNames bool
+
+	buf      bytes.Buffer
+	imports  map[string]bool
+	warnings []string
+	depth    int
+	helpers  map[pair]bool
+	pending  []pair
+}
+
+// loadPackage parses the non-test Go files in dir, skipping skipFile so a
+// previous output never feeds the next run.
+func loadPackage(dir, skipFile string) (*generator, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		types:   make(map[string]typeExpr),
+		methods: make(map[string][]string),
+		imports: make(map[string]bool),
+		helpers: make(map[pair]bool),
+	}
+
+	fset := token.NewFileSet()
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || name == skipFile {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		g.pkg = f.Name.Name
+		g.collect(f)
+	}
+
+	if g.pkg == "" {
+		return nil, fmt.Errorf("no Go files in %s", dir)
+	}
+	return g, nil
+}
+
+func (g *generator) collect(f *ast.File) {
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				if ts, ok := spec.(*ast.TypeSpec); ok {
+					g.types[ts.Name.Name] = typeExpr{ts.Type, f}
+				}
+			}
+		case *ast.FuncDecl:
+			if d.Recv == nil || len(d.Recv.List) == 0 {
+				continue
+			}
+			recv := d.Recv.List[0].Type
+			if star, ok := recv.(*ast.StarExpr); ok {
+				recv = star.X
+			}
+			if id, ok := recv.(*ast.Ident); ok {
+				g.methods[id.Name] = append(g.methods[id.Name], d.Name.Name)
+			}
+		}
+	}
+}
+
+// generate returns the formatted source of one mapping function per pair.
+func (g *generator) generate(pairs []pair) ([]byte, error) {
+	var body bytes.Buffer
+	for _, p := range pairs {
+		doc, ok := g.types[p.Doc]
+		if !ok {
+			return nil, fmt.Errorf("type %s not found", p.Doc)
+		}
+		form, ok := g.types[p.Form]
+		if !ok {
+			return nil, fmt.Errorf("type %s not found", p.Form)
+		}
+		docStruct, ok := doc.expr.(*ast.StructType)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a struct", p.Doc)
+		}
+		formStruct, ok := form.expr.(*ast.StructType)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a struct", p.Form)
+		}
+
+		name := "Map" + upperFirst(p.Doc) + "ToForm"
+		g.buf.Reset()
+		fmt.Fprintf(&g.buf, "// %s maps doc into form as formmap.NewMapper().MapToForm would, without\n// reflection.\n", name)
+		fmt.Fprintf(&g.buf, "func %s(doc *%s, valErr *formmap.ValidationError, form *%s) {\n", name, p.Doc, p.Form)
+		g.emitStruct("doc", typeExpr{docStruct, doc.file}, "form", typeExpr{formStruct, form.file}, path{})
+		g.buf.WriteString("}\n\n")
+		body.Write(g.buf.Bytes())
+	}
+
+	// Nested named structs get a helper each, so recursive types terminate.
+	for len(g.pending) > 0 {
+		p := g.pending[0]
+		g.pending = g.pending[1:]
+
+		doc, form := g.types[p.Doc], g.types[p.Form]
+		g.buf.Reset()
+		fmt.Fprintf(&g.buf, "func %s(doc *%s, valErr *formmap.ValidationError, form *%s, path string) {\n", helperName(p), p.Doc, p.Form)
+		g.emitStruct("doc", doc, "form", form, path{parts: []pathPart{{expr: "path", lit: p.Doc}}})
+		g.buf.WriteString("}\n\n")
+		body.Write(g.buf.Bytes())
+	}
+
+	var out bytes.Buffer
+	out.WriteString("// Code generated by formmap-gen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", g.pkg)
+	out.WriteString("import (\n")
+	for _, imp := range slices.Sorted(maps.Keys(g.imports)) {
+		fmt.Fprintf(&out, "\t%q\n", imp)
+	}
+	fmt.Fprintf(&out, "\n\t%q\n)\n\n", formmapPath)
+	out.Write(body.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return out.Bytes(), fmt.Errorf("formatting generated code: %w", err)
+	}
+	return src, nil
+}
+
+// fieldOptions holds the parts of the formmap and default tags the generator
+// honours.
+type fieldOptions struct {
+	layout, unit, def string
+}
+
+func (g *generator) emitStruct(docExpr string, docT typeExpr, formExpr string, formT typeExpr, p path) {
+	docStruct := docT.expr.(*ast.StructType)
+	formStruct := formT.expr.(*ast.StructType)
+
+	for _, field := range docStruct.Fields.List {
+		if len(field.Names) == 0 {
+			g.skip(p.child(exprString(field.Type)), "embedded fields are not supported")
+			continue
+		}
+
+		var tag reflect.StructTag
+		if field.Tag != nil {
+			raw, _ := strconv.Unquote(field.Tag.Value)
+			tag = reflect.StructTag(raw)
+		}
+
+		for _, ident := range field.Names {
+			if !ident.IsExported() {
+				continue
+			}
+
+			name := ident.Name
+			if g.jsonNames {
+				if jsonName, _, _ := strings.Cut(tag.Get("json"), ","); jsonName != "" {
+					name = jsonName
+				}
+			}
+			if name == "-" {
+				continue
+			}
+
+			formName, opts := parseTag(tag.Get("formmap"))
+			if formName == "-" {
+				continue
+			}
+			if formName == "" {
+				formName = ident.Name
+			}
+			opts.def = tag.Get("default")
+
+			formField, ok := findField(formStruct, formName)
+			if !ok {
+				continue
+			}
+
+			g.emitValue(docExpr+"."+ident.Name, typeExpr{field.Type, docT.file},
+				formExpr+"."+formName, typeExpr{formField.Type, formT.file}, p.child(name), opts)
+		}
+	}
+}
+
+func (g *generator) emitValue(docExpr string, docT typeExpr, formExpr string, formT typeExpr, p path, opts fieldOptions) {
+	if g.isFormLeaf(formT) {
+		g.emitLeaf(docExpr, docT, formExpr, p, opts)
+		return
+	}
+
+	if np, ok := g.namedStructs(docT, formT); ok {
+		if !g.helpers[np] {
+			g.helpers[np] = true
+			g.pending = append(g.pending, np)
+		}
+		fmt.Fprintf(&g.buf, "%s(%s, valErr, %s, %s)\n", helperName(np), addr(docExpr), addr(formExpr), p.expr())
+		return
+	}
+
+	docT = g.resolve(docT)
+	formT = g.resolve(formT)
+
+	docStar, docIsPtr := docT.expr.(*ast.StarExpr)
+	formStar, formIsPtr := formT.expr.(*ast.StarExpr)
+
+	switch {
+	case docIsPtr && !formIsPtr:
+		fmt.Fprintf(&g.buf, "if %s != nil {\n", docExpr)
+		g.emitValue("(*"+docExpr+")", typeExpr{docStar.X, docT.file}, formExpr, formT, p, opts)
+		g.buf.WriteString("}\n")
+
+	case docIsPtr && formIsPtr:
+		formElem, err := g.typeString(typeExpr{formStar.X, formT.file})
+		if err != nil {
+			g.skip(p, err.Error())
+			return
+		}
+		fmt.Fprintf(&g.buf, "if %s == nil {\n%s = nil\n} else {\n", docExpr, formExpr)
+		fmt.Fprintf(&g.buf, "if %s == nil {\n%s = new(%s)\n}\n", formExpr, formExpr, formElem)
+		g.emitValue("(*"+docExpr+")", typeExpr{docStar.X, docT.file}, "(*"+formExpr+")", typeExpr{formStar.X, formT.file}, p, opts)
+		g.buf.WriteString("}\n")
+
+	case isSlice(docT.expr) && isSlice(formT.expr):
+		g.emitSlice(docExpr, docT, formExpr, formT, p, opts)
+
+	case isStruct(docT.expr) && isStruct(formT.expr):
+		g.emitStruct(docExpr, docT, formExpr, formT, p)
+
+	default:
+		g.skip(p, fmt.Sprintf("cannot map %s onto %s", exprString(docT.expr), exprString(formT.expr)))
+	}
+}
+
+func (g *generator) emitSlice(docExpr string, docT typeExpr, formExpr string, formT typeExpr, p path, opts fieldOptions) {
+	formElem, err := g.typeString(typeExpr{formT.expr.(*ast.ArrayType).Elt, formT.file})
+	if err != nil {
+		g.skip(p, err.Error())
+		return
+	}
+
+	g.use("strconv")
+	i := "i"
+	if g.depth > 0 {
+		i += strconv.Itoa(g.depth)
+	}
+	g.depth++
+	defer func() { g.depth-- }()
+
+	// Same rule as the reflection mapper without merge: a slice as long as
+	// the document keeps its elements, a resized one starts fresh, whatever
+	// its capacity.
+	fmt.Fprintf(&g.buf, "if n := len(%s); len(%s) != n {\n", docExpr, formExpr)
+	fmt.Fprintf(&g.buf, "if cap(%s) >= n {\n%s = %s[:n]\nclear(%s)\n", formExpr, formExpr, formExpr, formExpr)
+	fmt.Fprintf(&g.buf, "} else {\n%s = make([]%s, n)\n}\n}\n", formExpr, formElem)
+
+	fmt.Fprintf(&g.buf, "for %s := range %s {\n", i, docExpr)
+	g.emitValue(docExpr+"["+i+"]", typeExpr{docT.expr.(*ast.ArrayType).Elt, docT.file},
+		formExpr+"["+i+"]", typeExpr{formT.expr.(*ast.ArrayType).Elt, formT.file}, p.index(i), opts)
+	g.buf.WriteString("}\n")
+}
+
+func (g *generator) emitLeaf(docExpr string, docT typeExpr, formExpr string, p path, opts fieldOptions) {
+	cond, value, err := g.leafValue(docExpr, docT, opts)
+	if err != nil {
+		g.skip(p, err.Error())
+		return
+	}
+
+	if cond == "" {
+		fmt.Fprintf(&g.buf, "%s.Value = %s\n", formExpr, value)
+	} else {
+		fmt.Fprintf(&g.buf, "%s.Value = \"\"\nif %s {\n%s.Value = %s\n}\n", formExpr, cond, formExpr, value)
+	}
+	if opts.def != "" {
+		fmt.Fprintf(&g.buf, "if %s.Value == \"\" {\n%s.Value = %q\n}\n", formExpr, formExpr, opts.def)
+	}
+	fmt.Fprintf(&g.buf, "%s.Error = valErr.MsgFor(%s)\n", formExpr, p.expr())
+}
+
+// leafValue returns the expression converting expr to its form string and the
+// condition under which it applies; otherwise the value is empty. It follows
+// the default converters and the zero-value rule of the reflection mapper.
+func (g *generator) leafValue(expr string, t typeExpr, opts fieldOptions) (cond, value string, err error) {
+	switch e := t.expr.(type) {
+	case *ast.StarExpr:
+		cond, value, err = g.leafValue("(*"+expr+")", typeExpr{e.X, t.file}, opts)
+		return joinCond(expr+" != nil", cond), value, err
+
+	case *ast.ArrayType:
+		if e.Len == nil && isIdent(e.Elt, "byte", "uint8") {
+			g.imports["encoding/base64"] = true
+			return "len(" + expr + ") > 0", "base64.StdEncoding.EncodeToString(" + expr + ")", nil
+		}
+
+	case *ast.SelectorExpr:
+		switch g.importPath(t.file, e) + "." + e.Sel.Name {
+		case "time.Time":
+			layout := strconv.Quote(opts.layout)
+			if opts.layout == "" {
+				layout = "time.RFC3339"
+				g.use("time")
+			}
+			return "!" + expr + ".IsZero()", expr + ".Format(" + layout + ")", nil
+		case "time.Duration":
+			value, err := g.durationValue(expr, opts.unit)
+			return expr + " != 0", value, err
+		}
+
+	case *ast.Ident:
+		if value, zero, ok := g.basicValue(expr, e.Name, true); ok {
+			if e.Name == "string" {
+				zero = ""
+			}
+			return zero, value, nil
+		}
+		return g.namedValue(expr, e.Name, opts)
+	}
+
+	return "", "", fmt.Errorf("no generated conversion for %s", exprString(t.expr))
+}
+
+// namedValue converts a value of a named type declared in the package: a
+// String method wins, otherwise the underlying basic type is used.
+func (g *generator) namedValue(expr, name string, opts fieldOptions) (cond, value string, err error) {
+	underlying, ok := g.types[name]
+	if !ok {
+		return "", "", fmt.Errorf("unknown type %s", name)
+	}
+	methods := g.methods[name]
+	if slices.Contains(methods, "MarshalText") {
+		return "", "", fmt.Errorf("%s implements encoding.TextMarshaler", name)
+	}
+
+	ident, isBasic := underlying.expr.(*ast.Ident)
+	if isBasic {
+		if value, cond, ok = g.basicValue(expr, ident.Name, false); !ok {
+			return g.namedValue(expr, ident.Name, opts)
+		}
+	}
+	hasIsZero := slices.Contains(methods, "IsZero")
+	if hasIsZero {
+		cond = "!" + expr + ".IsZero()"
+	}
+
+	switch hasString := slices.Contains(methods, "String"); {
+	case hasString && (isBasic || hasIsZero):
+		return cond, expr + ".String()", nil
+	case isBasic:
+		return cond, value, nil
+	case hasString:
+		return "", "", fmt.Errorf("%s needs an IsZero method to be generated", name)
+	}
+	return "", "", fmt.Errorf("no generated conversion for %s", name)
+}
+
+// basicValue converts a value whose underlying type is the predeclared type
+// name. exact is false for named types, which need converting first and which
+// the reflection mapper formats by kind rather than through the built-in
+// float32 converter.
+func (g *generator) basicValue(expr, name string, exact bool) (value, zero string, ok bool) {
+	as := func(t string) string {
+		if exact && t == name {
+			return expr
+		}
+		return t + "(" + expr + ")"
+	}
+
+	switch name {
+	case "string":
+		return as("string"), expr + ` != ""`, true
+	case "bool":
+		return "strconv.FormatBool(" + as("bool") + ")", "", g.use("strconv")
+	case "int", "int8", "int16", "int32", "int64", "rune":
+		return "strconv.FormatInt(" + as("int64") + ", 10)", expr + " != 0", g.use("strconv")
+	case "uint", "uint8", "uint16", "uint32", "uint64", "uintptr", "byte":
+		return "strconv.FormatUint(" + as("uint64") + ", 10)", expr + " != 0", g.use("strconv")
+	case "float32":
+		if exact {
+			return "strconv.FormatFloat(float64(" + expr + "), 'f', -1, 32)", expr + " != 0", g.use("strconv")
+		}
+		fallthrough
+	case "float64":
+		return "strconv.FormatFloat(" + as("float64") + ", 'f', -1, 64)", expr + " != 0", g.use("strconv")
+	}
+	return "", "", false
+}
+
+func (g *generator) durationValue(expr, unit string) (string, error) {
+	switch unit {
+	case "seconds":
+		g.use("strconv")
+		return "strconv.Itoa(int(" + expr + ".Seconds()))", nil
+	case "hours":
+		g.use("strconv")
+		return "strconv.FormatFloat(" + expr + ".Hours(), 'f', -1, 64)", nil
+	case "string":
+		return expr + ".String()", nil
+	case "", "minutes":
+		g.use("strconv")
+		return "strconv.Itoa(int(" + expr + ".Minutes()))", nil
+	}
+	return "", fmt.Errorf("unknown duration unit %q", unit)
+}
+
+func (g *generator) use(imp string) bool {
+	g.imports[imp] = true
+	return true
+}
+
+// skip leaves a note in the generated code and reports the field, so missing
+// conversions are visible rather than silently dropped.
+func (g *generator) skip(p path, reason string) {
+	fmt.Fprintf(&g.buf, "// %s: not generated: %s\n", p.pattern(), reason)
+	g.warnings = append(g.warnings, p.pattern()+": "+reason)
+}
+
+// namedStructs reports whether both types are struct types declared in the
+// package, which are mapped through a helper function.
+func (g *generator) namedStructs(docT, formT typeExpr) (pair, bool) {
+	docID, ok := docT.expr.(*ast.Ident)
+	if !ok {
+		return pair{}, false
+	}
+	formID, ok := formT.expr.(*ast.Ident)
+	if !ok {
+		return pair{}, false
+	}
+	if !isStruct(g.types[docID.Name].expr) || !isStruct(g.types[formID.Name].expr) {
+		return pair{}, false
+	}
+	return pair{Doc: docID.Name, Form: formID.Name}, true
+}
+
+func helperName(p pair) string {
+	return "map" + upperFirst(p.Doc) + "To" + upperFirst(p.Form)
+}
+
+func (g *generator) isFormLeaf(t typeExpr) bool {
+	sel, ok := t.expr.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "FormInputData" && g.importPath(t.file, sel) == formmapPath
+}
+
+// resolve replaces a package-level type name with its definition when that is
+// a struct, slice or pointer, so they can be walked.
+func (g *generator) resolve(t typeExpr) typeExpr {
+	if id, ok := t.expr.(*ast.Ident); ok {
+		if def, ok := g.types[id.Name]; ok {
+			switch def.expr.(type) {
+			case *ast.StructType, *ast.ArrayType, *ast.StarExpr:
+				return def
+			}
+		}
+	}
+	return t
+}
+
+// typeString renders t for use in the generated file.
+func (g *generator) typeString(t typeExpr) (string, error) {
+	switch e := t.expr.(type) {
+	case *ast.Ident:
+		return e.Name, nil
+	case *ast.StarExpr:
+		s, err := g.typeString(typeExpr{e.X, t.file})
+		return "*" + s, err
+	case *ast.SelectorExpr:
+		if g.importPath(t.file, e) == formmapPath {
+			return "formmap." + e.Sel.Name, nil
+		}
+	}
+	return "", fmt.Errorf("cannot name form type %s in generated code", exprString(t.expr))
+}
+
+func (g *generator) importPath(f *ast.File, sel *ast.SelectorExpr) string {
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	for _, imp := range f.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		name := path[strings.LastIndex(path, "/")+1:]
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		if name == pkg.Name {
+			return path
+		}
+	}
+	return ""
+}
+
+// parseTag reads the name and the layout= and unit= options of a formmap tag.
+// layout= and sep= take the rest of the tag, as in the formmap package.
+func parseTag(tag string) (name string, opts fieldOptions) {
+	for first := true; tag != ""; first = false {
+		var part string
+		if strings.HasPrefix(tag, "layout=") || strings.HasPrefix(tag, "sep=") {
+			part, tag = tag, ""
+		} else {
+			part, tag, _ = strings.Cut(tag, ",")
+		}
+
+		key, value, isOption := strings.Cut(part, "=")
+		switch {
+		case !isOption && first:
+			name = part
+		case key == "layout":
+			opts.layout = value
+		case key == "unit":
+			opts.unit = value
+		}
+	}
+	return name, opts
+}
+
+func findField(st *ast.StructType, name string) (*ast.Field, bool) {
+	for _, field := range st.Fields.List {
+		for _, ident := range field.Names {
+			if ident.Name == name && ident.IsExported() {
+				return field, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// addr takes the address of expr, undoing a dereference.
+func addr(expr string) string {
+	if inner, ok := strings.CutPrefix(expr, "(*"); ok && strings.HasSuffix(inner, ")") {
+		return strings.TrimSuffix(inner, ")")
+	}
+	return "&" + expr
+}
+
+func isSlice(e ast.Expr) bool {
+	at, ok := e.(*ast.ArrayType)
+	return ok && at.Len == nil
+}
+
+func isStruct(e ast.Expr) bool {
+	_, ok := e.(*ast.StructType)
+	return ok
+}
+
+func isIdent(e ast.Expr, names ...string) bool {
+	id, ok := e.(*ast.Ident)
+	return ok && slices.Contains(names, id.Name)
+}
+
+func joinCond(a, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	}
+	return a + " && " + b
+}
+
+func exprString(e ast.Expr) string {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, token.NewFileSet(), e); err != nil {
+		return fmt.Sprintf("%T", e)
+	}
+	return buf.String()
+}
+
+func upperFirst(s string) string {
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// path builds the Go expression for a field path, formatting indices only at
+// run time. Helpers receive their prefix in a variable.
+type path struct {
+	parts []pathPart
+}
+
+// pathPart is literal text, an index variable or, with expr set, a string
+// variable whose pattern is lit.
+type pathPart struct {
+	lit   string
+	index string
+	expr  string
+}
+
+func (p path) child(name string) path {
+	if len(p.parts) == 0 {
+		return path{parts: []pathPart{{lit: name}}}
+	}
+	return path{parts: append(slices.Clip(p.parts), pathPart{lit: "." + name})}
+}
+
+func (p path) index(i string) path {
+	return path{parts: append(slices.Clip(p.parts), pathPart{index: i})}
+}
+
+// expr renders the path as a string expression, e.g.
+// "Items[" + strconv.Itoa(i) + "].Price".
+func (p path) expr() string {
+	var terms []string
+	lit := ""
+	flush := func() {
+		if lit != "" {
+			terms = append(terms, strconv.Quote(lit))
+			lit = ""
+		}
+	}
+	for _, part := range p.parts {
+		switch {
+		case part.expr != "":
+			flush()
+			terms = append(terms, part.expr)
+		case part.index != "":
+			lit += "["
+			flush()
+			terms = append(terms, "strconv.Itoa("+part.index+")")
+			lit = "]"
+		default:
+			lit += part.lit
+		}
+	}
+	flush()
+	return strings.Join(terms, " + ")
+}
+
+// pattern renders the path with "[*]" for indices, for notes and warnings.
+func (p path) pattern() string {
+	var b strings.Builder
+	for _, part := range p.parts {
+		switch {
+		case part.index != "":
+			b.WriteString("[*]")
+		case part.expr != "":
+			b.WriteString("<" + part.lit + ">")
+		default:
+			b.WriteString(part.lit)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerate_ExampleUpToDate(t *testing.T) {
+	dir := filepath.Join("internal", "example")
+	g, err := loadPackage(dir, "product_formmap.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := g.generate([]pair{{Doc: "Product", Form: "ProductForm"}})
+	if err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+
+	checked, err := os.ReadFile(filepath.Join(dir, "product_formmap.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(src, checked) {
+		t.Errorf("internal/example/product_formmap.go is stale; run go generate ./... in it")
+	}
+	if len(g.warnings) != 0 {
+		t.Errorf("warnings = %v", g.warnings)
+	}
+}
+
+func TestGenerate_Unsupported(t *testing.T) {
+	dir := t.TempDir()
+	src := `package shop
+
+import (
+	"time"
+
+	fm "github.com/omareloui/formmap"
+)
+
+type Base struct{ ID string }
+
+type Order struct {
+	Base
+	Lines map[string]int
+	Codes []string
+	Wait  time.Duration ` + "`formmap:\"unit=secs\"`" + `
+	Ref   string
+}
+
+type OrderForm struct {
+	Lines map[string]fm.FormInputData
+	Codes fm.FormInputData
+	Wait  fm.FormInputData
+	Ref   fm.FormInputData
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "shop.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := loadPackage(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := g.generate([]pair{{Doc: "Order", Form: "OrderForm"}})
+	if err != nil {
+		t.Fatalf("generate() error = %v\n%s", err, out)
+	}
+
+	want := []string{
+		"Base: embedded fields are not supported",
+		"Lines: cannot map map[string]int onto map[string]fm.FormInputData",
+		"Codes: no generated conversion for []string",
+		`Wait: unknown duration unit "secs"`,
+	}
+	if strings.Join(g.warnings, "\n") != strings.Join(want, "\n") {
+		t.Errorf("warnings = %q, want %q", g.warnings, want)
+	}
+	for _, w := range want {
+		if !strings.Contains(string(out), "// "+strings.Replace(w, ": ", ": not generated: ", 1)) {
+			t.Errorf("generated code lacks a note for %q", w)
+		}
+	}
+	if !strings.Contains(string(out), `form.Ref.Value = doc.Ref`) {
+		t.Errorf("supported field missing:\n%s", out)
+	}
+
+	if _, err := g.generate([]pair{{Doc: "Missing", Form: "OrderForm"}}); err == nil {
+		t.Error("generate() with an unknown type succeeded")
+	}
+}
+
+func TestPathExpr(t *testing.T) {
+	tests := []struct {
+		path path
+		want string
+	}{
+		{path{}.child("Name"), `"Name"`},
+		{path{}.child("Items").index("i").child("Price"), `"Items[" + strconv.Itoa(i) + "].Price"`},
+		{path{parts: []pathPart{{expr: "path", lit: "Item"}}}.child("Parts").index("i1"), `path + ".Parts[" + strconv.Itoa(i1) + "]"`},
+	}
+	for _, tt := range tests {
+		if got := tt.path.expr(); got != tt.want {
+			t.Errorf("expr() = %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestPairFlag(t *testing.T) {
+	var p pairFlag
+	if err := p.Set("Product=ProductForm, Order=OrderForm"); err != nil {
+		t.Fatal(err)
+	}
+	if p.String() != "Product=ProductForm,Order=OrderForm" {
+		t.Errorf("pairs = %s", p.String())
+	}
+	if err := p.Set("Product"); err == nil {
+		t.Error("Set(\"Product\") succeeded")
+	}
+}
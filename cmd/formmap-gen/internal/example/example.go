@@ -0,0 +1,72 @@
+// Package example holds the types formmap-gen's output is checked against.
+package example
+
+import (
+	"time"
+
+	"github.com/omareloui/formmap"
+)
+
+//go:generate go run github.com/omareloui/formmap/cmd/formmap-gen -type Product=ProductForm
+
+type Status string
+
+func (s Status) String() string { return "status:" + string(s) }
+
+type Product struct {
+	Name    string `default:"unnamed"`
+	Price   float64
+	Weight  float32
+	Qty     int
+	Active  bool
+	Status  Status
+	Created time.Time     `formmap:"layout=2006-01-02"`
+	Shelf   time.Duration `formmap:"unit=hours"`
+	Note    *string
+	Meta    Meta
+	Owner   *Meta
+	Items   []Item
+	Tags    []string
+	Raw     []byte
+	Secret  string `formmap:"-"`
+	Nick    string `formmap:"Alias"`
+}
+
+type Meta struct {
+	Author string
+}
+
+type Item struct {
+	SKU   string
+	Price float64
+	Parts []Item
+}
+
+type ProductForm struct {
+	Name    formmap.FormInputData
+	Price   formmap.FormInputData
+	Weight  formmap.FormInputData
+	Qty     formmap.FormInputData
+	Active  formmap.FormInputData
+	Status  formmap.FormInputData
+	Created formmap.FormInputData
+	Shelf   formmap.FormInputData
+	Note    formmap.FormInputData
+	Meta    MetaForm
+	Owner   *MetaForm
+	Items   []ItemForm
+	Tags    []formmap.FormInputData
+	Raw     formmap.FormInputData
+	Secret  formmap.FormInputData
+	Alias   formmap.FormInputData
+}
+
+type MetaForm struct {
+	Author formmap.FormInputData
+}
+
+type ItemForm struct {
+	SKU   formmap.FormInputData
+	Price formmap.FormInputData
+	Parts []ItemForm
+}
@@ -0,0 +1,89 @@
+package example
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/omareloui/formmap"
+)
+
+func TestMapProductToForm_MatchesMapper(t *testing.T) {
+	note := "fragile"
+	docs := map[string]*Product{
+		"zero": {},
+		"full": {
+			Name:    "Lamp",
+			Price:   19.99,
+			Weight:  1.1,
+			Qty:     3,
+			Active:  true,
+			Status:  "live",
+			Created: time.Date(2024, 3, 9, 10, 0, 0, 0, time.UTC),
+			Shelf:   90 * time.Minute,
+			Note:    &note,
+			Meta:    Meta{Author: "Ada"},
+			Owner:   &Meta{Author: "Grace"},
+			Items: []Item{
+				{SKU: "A", Price: 1, Parts: []Item{{SKU: "A.1"}}},
+				{SKU: "B"},
+			},
+			Tags:   []string{"new", ""},
+			Raw:    []byte{1, 2, 3},
+			Secret: "hidden",
+			Nick:   "lampy",
+		},
+	}
+
+	valErr := &formmap.ValidationError{Errors: formmap.Errors{
+		"Name":                  formmap.ValidationField{Tag: "required"},
+		"Meta.Author":           formmap.ValidationField{Tag: "required"},
+		"Items[0].Parts[0].SKU": formmap.ValidationField{Tag: "required"},
+		"Tags[1]":               formmap.ValidationField{Tag: "required"},
+		"Nick":                  formmap.ValidationField{Tag: "required"},
+	}}
+
+	// items returns n element forms with a pre-set label, in a slice of the
+	// given capacity.
+	items := func(n, capacity int) []ItemForm {
+		s := make([]ItemForm, n, capacity)
+		for i := range s {
+			s[i].SKU.Label = "SKU"
+		}
+		return s
+	}
+	// Pre-set labels and slice elements must be kept or dropped alike, as
+	// whether the slice matches the document or is resized in place or not.
+	shapes := map[string]func() []ItemForm{
+		"longer":       func() []ItemForm { return items(3, 5) },
+		"same length":  func() []ItemForm { return items(2, 2) },
+		"spare cap":    func() []ItemForm { return items(1, 4) },
+		"reallocating": func() []ItemForm { return items(1, 1) },
+	}
+
+	for name, doc := range docs {
+		for sliceName, newItems := range shapes {
+			t.Run(name+"/"+sliceName, func(t *testing.T) {
+				newForm := func() *ProductForm {
+					return &ProductForm{
+						Name:  formmap.FormInputData{Label: "Name", Value: "stale"},
+						Items: newItems(),
+						Owner: &MetaForm{},
+					}
+				}
+
+				want := newForm()
+				if err := formmap.NewMapper().MapToForm(doc, valErr, want); err != nil {
+					t.Fatalf("MapToForm() error = %v", err)
+				}
+
+				got := newForm()
+				MapProductToForm(doc, valErr, got)
+
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("generated = %+v\nmapper    = %+v", got, want)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Code generated by formmap-gen; DO NOT EDIT.
+
+package example
+
+import (
+	"encoding/base64"
+	"strconv"
+
+	"github.com/omareloui/formmap"
+)
+
+// MapProductToForm maps doc into form as formmap.NewMapper().MapToForm would, without
+// reflection.
+func MapProductToForm(doc *Product, valErr *formmap.ValidationError, form *ProductForm) {
+	form.Name.Value = doc.Name
+	if form.Name.Value == "" {
+		form.Name.Value = "unnamed"
+	}
+	form.Name.Error = valErr.MsgFor("Name")
+	form.Price.Value = ""
+	if doc.Price != 0 {
+		form.Price.Value = strconv.FormatFloat(doc.Price, 'f', -1, 64)
+	}
+	form.Price.Error = valErr.MsgFor("Price")
+	form.Weight.Value = ""
+	if doc.Weight != 0 {
+		form.Weight.Value = strconv.FormatFloat(float64(doc.Weight), 'f', -1, 32)
+	}
+	form.Weight.Error = valErr.MsgFor("Weight")
+	form.Qty.Value = ""
+	if doc.Qty != 0 {
+		form.Qty.Value = strconv.FormatInt(int64(doc.Qty), 10)
+	}
+	form.Qty.Error = valErr.MsgFor("Qty")
+	form.Active.Value = strconv.FormatBool(doc.Active)
+	form.Active.Error = valErr.MsgFor("Active")
+	form.Status.Value = ""
+	if doc.Status != "" {
+		form.Status.Value = doc.Status.String()
+	}
+	form.Status.Error = valErr.MsgFor("Status")
+	form.Created.Value = ""
+	if !doc.Created.IsZero() {
+		form.Created.Value = doc.Created.Format("2006-01-02")
+	}
+	form.Created.Error = valErr.MsgFor("Created")
+	form.Shelf.Value = ""
+	if doc.Shelf != 0 {
+		form.Shelf.Value = strconv.FormatFloat(doc.Shelf.Hours(), 'f', -1, 64)
+	}
+	form.Shelf.Error = valErr.MsgFor("Shelf")
+	form.Note.Value = ""
+	if doc.Note != nil {
+		form.Note.Value = (*doc.Note)
+	}
+	form.Note.Error = valErr.MsgFor("Note")
+	mapMetaToMetaForm(&doc.Meta, valErr, &form.Meta, "Meta")
+	if doc.Owner == nil {
+		form.Owner = nil
+	} else {
+		if form.Owner == nil {
+			form.Owner = new(MetaForm)
+		}
+		mapMetaToMetaForm(doc.Owner, valErr, form.Owner, "Owner")
+	}
+	if n := len(doc.Items); len(form.Items) != n {
+		if cap(form.Items) >= n {
+			form.Items = form.Items[:n]
+			clear(form.Items)
+		} else {
+			form.Items = make([]ItemForm, n)
+		}
+	}
+	for i := range doc.Items {
+		mapItemToItemForm(&doc.Items[i], valErr, &form.Items[i], "Items["+strconv.Itoa(i)+"]")
+	}
+	if n := len(doc.Tags); len(form.Tags) != n {
+		if cap(form.Tags) >= n {
+			form.Tags = form.Tags[:n]
+			clear(form.Tags)
+		} else {
+			form.Tags = make([]formmap.FormInputData, n)
+		}
+	}
+	for i := range doc.Tags {
+		form.Tags[i].Value = doc.Tags[i]
+		form.Tags[i].Error = valErr.MsgFor("Tags[" + strconv.Itoa(i) + "]")
+	}
+	form.Raw.Value = ""
+	if len(doc.Raw) > 0 {
+		form.Raw.Value = base64.StdEncoding.EncodeToString(doc.Raw)
+	}
+	form.Raw.Error = valErr.MsgFor("Raw")
+	form.Alias.Value = doc.Nick
+	form.Alias.Error = valErr.MsgFor("Nick")
+}
+
+func mapMetaToMetaForm(doc *Meta, valErr *formmap.ValidationError, form *MetaForm, path string) {
+	form.Author.Value = doc.Author
+	form.Author.Error = valErr.MsgFor(path + ".Author")
+}
+
+func mapItemToItemForm(doc *Item, valErr *formmap.ValidationError, form *ItemForm, path string) {
+	form.SKU.Value = doc.SKU
+	form.SKU.Error = valErr.MsgFor(path + ".SKU")
+	form.Price.Value = ""
+	if doc.Price != 0 {
+		form.Price.Value = strconv.FormatFloat(doc.Price, 'f', -1, 64)
+	}
+	form.Price.Error = valErr.MsgFor(path + ".Price")
+	if n := len(doc.Parts); len(form.Parts) != n {
+		if cap(form.Parts) >= n {
+			form.Parts = form.Parts[:n]
+			clear(form.Parts)
+		} else {
+			form.Parts = make([]ItemForm, n)
+		}
+	}
+	for i := range doc.Parts {
+		mapItemToItemForm(&doc.Parts[i], valErr, &form.Parts[i], path+".Parts["+strconv.Itoa(i)+"]")
+	}
+}
@@ -0,0 +1,86 @@
+// Command formmap-gen writes reflection-free versions of formmap's MapToForm
+// for pairs of document and form structs, for hot paths that can't afford
+// reflection. Run it through go:generate in the package declaring the types:
+//
+//	//go:generate go run github.com/omareloui/formmap/cmd/formmap-gen -type Product=ProductForm
+//
+// Each pair becomes a MapProductToForm(doc, valErr, form) function using
+// plain assignments, the default converters and ValidationError.MsgFor.
+// Fields the generator can't convert without reflection are reported and
+// left out with a note in the generated code.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type pairFlag []pair
+
+func (p *pairFlag) String() string {
+	parts := make([]string, len(*p))
+	for i, pr := range *p {
+		parts[i] = pr.Doc + "=" + pr.Form
+	}
+	return strings.Join(parts, ",")
+}
+
+func (p *pairFlag) Set(value string) error {
+	for _, part := range strings.Split(value, ",") {
+		doc, form, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok || doc == "" || form == "" {
+			return fmt.Errorf("want Doc=Form, got %q", part)
+		}
+		*p = append(*p, pair{Doc: doc, Form: form})
+	}
+	return nil
+}
+
+func main() {
+	var pairs pairFlag
+	flag.Var(&pairs, "type", "document and form types as Doc=Form; repeat or separate with commas")
+	output := flag.String("output", "", "output file name; default <doc>_formmap.go")
+	jsonNames := flag.Bool("json", false, "use json tag names in error paths, as formmap.WithJSONNames")
+	flag.Parse()
+
+	if err := run(pairs, *output, *jsonNames, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "formmap-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(pairs []pair, output string, jsonNames bool, args []string) error {
+	if len(pairs) == 0 {
+		return fmt.Errorf("no -type given")
+	}
+
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+	if output == "" {
+		output = strings.ToLower(pairs[0].Doc) + "_formmap.go"
+	}
+	if !filepath.IsAbs(output) {
+		output = filepath.Join(dir, output)
+	}
+
+	g, err := loadPackage(dir, filepath.Base(output))
+	if err != nil {
+		return err
+	}
+	g.jsonNames = jsonNames
+
+	src, err := g.generate(pairs)
+	if err != nil {
+		return err
+	}
+	for _, w := range g.warnings {
+		fmt.Fprintln(os.Stderr, "formmap-gen: skipped", w)
+	}
+
+	return os.WriteFile(output, src, 0o644)
+}
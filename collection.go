@@ -0,0 +1,177 @@
+package formmap
+
+import (
+	"cmp"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// isScalarList reports whether v is a slice or array that maps into a single
+// form value, such as Tags []string into one text input. Byte slices are
+// encoded as a whole instead.
+func isScalarList(v reflect.Value) bool {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return false
+		}
+		v = v.Elem()
+	}
+	if !isList(v) || isBytes(v.Type()) {
+		return false
+	}
+
+	switch v.Type().Elem().Kind() {
+	case reflect.Uint8, reflect.Struct, reflect.Map, reflect.Slice, reflect.Array, reflect.Interface, reflect.Func, reflect.Chan:
+		return false
+	}
+	return true
+}
+
+// joinsList reports whether v should be joined into a single form value
+// rather than formatted by a converter or its own text methods.
+func (m *Mapper) joinsList(v reflect.Value) bool {
+	if !isScalarList(v) {
+		return false
+	}
+
+	t := v.Type()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if conditional, converter, _ := m.convertersFor(t); converter != nil || len(conditional) > 0 {
+		return false
+	}
+
+	for _, iface := range []reflect.Type{textMarshalerType, stringerType} {
+		if t.Implements(iface) || reflect.PointerTo(t).Implements(iface) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *Mapper) joinList(st *mapState, v, docParent reflect.Value, sep string) string {
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	parts := make([]string, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		// Scalar elements always convert, even in strict mode.
+		if s, _ := m.convertFieldValue(st, v.Index(i), docParent); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+// splitList is the inverse of joinList. Surrounding spaces are dropped, so
+// "a, b,c" splits on ", " as well as ",".
+func splitList(s, sep string) []string {
+	var raw []string
+	if trimmed := strings.TrimSpace(sep); trimmed != "" {
+		raw = strings.Split(s, trimmed)
+	} else {
+		raw = strings.Fields(s)
+	}
+
+	parts := raw[:0]
+	for _, p := range raw {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
+
+// FormSliceMeta carries the collection-level limits of a slice field so that
+// templates can enable or disable "add" and "remove" controls. A form struct
+// opts in by declaring a field named after the slice field with a "Meta"
+// suffix, e.g. Tags []FormInputData alongside TagsMeta FormSliceMeta.
+type FormSliceMeta struct {
+	MinItems int
+	MaxItems int
+	// HasMax makes a MaxItems of zero a bound, as for max=0 or lt=1.
+	HasMax bool
+	Count  int
+}
+
+// CanAdd reports whether another item may be appended. A MaxItems of zero
+// without HasMax means the collection has no upper bound.
+func (s FormSliceMeta) CanAdd() bool {
+	if !s.HasMax && s.MaxItems == 0 {
+		return true
+	}
+	return s.Count < s.MaxItems
+}
+
+// CanRemove reports whether an item may be removed without going below MinItems.
+func (s FormSliceMeta) CanRemove() bool {
+	return s.Count > s.MinItems
+}
+
+const sliceMetaSuffix = "Meta"
+
+func (m *Mapper) mapSliceMeta(docField reflect.StructField, docFieldVal, formVal reflect.Value, formFieldName string) {
+	metaVal := formVal.FieldByName(formFieldName + sliceMetaSuffix)
+	if !metaVal.IsValid() || !metaVal.CanSet() || metaVal.Type() != reflect.TypeOf(FormSliceMeta{}) {
+		return
+	}
+
+	minItems, maxItems, hasMax := collectionLimits(docField.Tag.Get(cmp.Or(m.validateTag, "validate")))
+
+	count := 0
+	if docFieldVal.Kind() == reflect.Slice || docFieldVal.Kind() == reflect.Array {
+		count = docFieldVal.Len()
+	}
+
+	metaVal.Set(reflect.ValueOf(FormSliceMeta{
+		MinItems: minItems,
+		MaxItems: maxItems,
+		HasMax:   hasMax,
+		Count:    count,
+	}))
+}
+
+// collectionLimits extracts the min, max and len rules that apply to the
+// collection itself, i.e. the ones declared before "dive". Exclusive gt and
+// lt bounds are turned into item counts; hasMax reports whether there is an
+// upper bound, which may be zero.
+func collectionLimits(tag string) (minItems, maxItems int, hasMax bool) {
+	for _, rule := range strings.Split(tag, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "dive" {
+			break
+		}
+
+		name, param, ok := strings.Cut(rule, "=")
+		if !ok {
+			if name == "required" && minItems == 0 {
+				minItems = 1
+			}
+			continue
+		}
+
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			continue
+		}
+
+		switch name {
+		case "min", "gte":
+			minItems = n
+		case "gt":
+			minItems = n + 1
+		case "max", "lte":
+			maxItems, hasMax = n, true
+		case "lt":
+			maxItems, hasMax = n-1, true
+		case "len":
+			minItems, maxItems, hasMax = n, n, true
+		}
+	}
+
+	return max(minItems, 0), max(maxItems, 0), hasMax
+}
@@ -0,0 +1,173 @@
+package formmap
+
+import (
+	"reflect"
+	"testing"
+)
+
+type TestCollectionDoc struct {
+	Tags  []string   `validate:"min=1,max=3,dive,min=2"`
+	Items []TestItem `validate:"required,dive"`
+	Notes []string
+}
+
+type TestCollectionForm struct {
+	Tags      []FormInputData
+	TagsMeta  FormSliceMeta
+	Items     []TestItemForm
+	ItemsMeta FormSliceMeta
+	Notes     []FormInputData
+	NotesMeta FormSliceMeta
+}
+
+func TestMapper_MapToForm_SliceMeta(t *testing.T) {
+	mapper := NewMapper()
+
+	doc := &TestCollectionDoc{
+		Tags:  []string{"go", "web", "forms"},
+		Items: []TestItem{{ItemID: "1"}},
+		Notes: []string{"a", "b"},
+	}
+	form := &TestCollectionForm{}
+
+	if err := mapper.MapToForm(doc, nil, form); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		got       FormSliceMeta
+		want      FormSliceMeta
+		canAdd    bool
+		canRemove bool
+	}{
+		{"Tags", form.TagsMeta, FormSliceMeta{MinItems: 1, MaxItems: 3, HasMax: true, Count: 3}, false, true},
+		{"Items", form.ItemsMeta, FormSliceMeta{MinItems: 1, MaxItems: 0, Count: 1}, true, false},
+		{"Notes", form.NotesMeta, FormSliceMeta{MinItems: 0, MaxItems: 0, Count: 2}, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("meta = %+v, want %+v", tt.got, tt.want)
+			}
+			if tt.got.CanAdd() != tt.canAdd {
+				t.Errorf("CanAdd() = %v, want %v", tt.got.CanAdd(), tt.canAdd)
+			}
+			if tt.got.CanRemove() != tt.canRemove {
+				t.Errorf("CanRemove() = %v, want %v", tt.got.CanRemove(), tt.canRemove)
+			}
+		})
+	}
+}
+
+func TestCollectionLimits(t *testing.T) {
+	tests := []struct {
+		tag      string
+		min, max int
+		hasMax   bool
+	}{
+		{"", 0, 0, false},
+		{"min=2,max=5,dive,required", 2, 5, true},
+		{"len=4,dive,max=1", 4, 4, true},
+		{"gt=0,lt=10", 1, 9, true},
+		{"omitempty,dive,min=3", 0, 0, false},
+		{"max=0", 0, 0, true},
+		{"lte=0", 0, 0, true},
+		{"lt=1", 0, 0, true},
+		{"lt=0", 0, 0, true},
+		{"gt=2,lte=2", 3, 2, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			gotMin, gotMax, gotHasMax := collectionLimits(tt.tag)
+			if gotMin != tt.min || gotMax != tt.max || gotHasMax != tt.hasMax {
+				t.Errorf("collectionLimits(%q) = (%d, %d, %v), want (%d, %d, %v)", tt.tag, gotMin, gotMax, gotHasMax, tt.min, tt.max, tt.hasMax)
+			}
+		})
+	}
+}
+
+func TestMapper_SliceMeta_ValidateTag(t *testing.T) {
+	type doc struct {
+		Tags  []string `form:"min=1,max=2"`
+		Flags []string `form:"lt=1"`
+	}
+	type form struct {
+		Tags      []FormInputData
+		TagsMeta  FormSliceMeta
+		Flags     []FormInputData
+		FlagsMeta FormSliceMeta
+	}
+
+	f := &form{}
+	if err := NewMapper(WithValidateTag("form")).MapToForm(&doc{Tags: []string{"go"}}, nil, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+	if want := (FormSliceMeta{MinItems: 1, MaxItems: 2, HasMax: true, Count: 1}); f.TagsMeta != want {
+		t.Errorf("TagsMeta = %+v, want %+v", f.TagsMeta, want)
+	}
+	if !f.TagsMeta.CanAdd() {
+		t.Error("TagsMeta.CanAdd() = false, want true")
+	}
+	// lt=1 allows no items, rather than any number.
+	if want := (FormSliceMeta{HasMax: true}); f.FlagsMeta != want || f.FlagsMeta.CanAdd() {
+		t.Errorf("FlagsMeta = %+v, CanAdd() = %v; want %+v, false", f.FlagsMeta, f.FlagsMeta.CanAdd(), want)
+	}
+}
+
+func TestMapper_JoinedList(t *testing.T) {
+	type post struct {
+		Tags    []string
+		Scores  []int `formmap:"sep=|"`
+		Aliases *[]string
+		Empty   []string
+	}
+	type postForm struct {
+		Tags    FormInputData
+		Scores  FormInputData
+		Aliases FormInputData
+		Empty   FormInputData
+	}
+
+	aliases := []string{"a", "b"}
+	d := &post{Tags: []string{"go", "forms", "html"}, Scores: []int{3, 14}, Aliases: &aliases}
+
+	mapper := NewMapper()
+	if err := mapper.ApplyConfig(&Config{Fields: map[string]FieldConfig{"Aliases": {Separator: " / "}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	f := &postForm{}
+	if err := mapper.MapToForm(d, nil, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	if f.Tags.Value != "go, forms, html" {
+		t.Errorf("Tags = %q", f.Tags.Value)
+	}
+	if f.Scores.Value != "3|14" {
+		t.Errorf("Scores = %q", f.Scores.Value)
+	}
+	if f.Aliases.Value != "a / b" {
+		t.Errorf("Aliases = %q", f.Aliases.Value)
+	}
+	if f.Empty.Value != "" {
+		t.Errorf("Empty = %q", f.Empty.Value)
+	}
+
+	f.Tags.Value = " go,forms ,, html "
+	back := &post{}
+	if err := mapper.MapFromForm(f, back); err != nil {
+		t.Fatalf("MapFromForm() error = %v", err)
+	}
+	if !reflect.DeepEqual(back, d) {
+		t.Errorf("MapFromForm() = %+v, want %+v", back, d)
+	}
+
+	f.Scores.Value = "3|x"
+	if err := mapper.MapFromForm(f, back); err == nil {
+		t.Error("expected error for unparsable item")
+	}
+}
@@ -0,0 +1,237 @@
+package formmap
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Plan is a mapping between one document type and one form type, checked
+// and resolved ahead of time by Compile.
+type Plan struct {
+	m        *Mapper
+	docType  reflect.Type
+	formType reflect.Type
+	version  uint64
+	structs  map[string]*compiledStruct
+}
+
+// compiledStruct is a struct pair at a fixed path, with the paths, form
+// fields, configuration and field mappers of its fields resolved.
+type compiledStruct struct {
+	docType, formType reflect.Type
+	fields            []structField
+}
+
+// Compile checks that docType maps onto formType and warms the mapper's
+// caches for every struct pair on the way, so mismatches surface at startup
+// rather than as silently empty form fields. It reports, as joined
+// *FieldError values, fields renamed to form fields that don't exist and
+// fields whose form counterpart has a shape they can't map into. Document
+// fields with no form field of the same name are left out, as in MapToForm.
+//
+// The plan resolves the fields of the document and of the structs nested in
+// it up front, so Execute maps them without looking up paths, configuration
+// or field mappers. Elements of slices and maps are still resolved per call.
+func (m *Mapper) Compile(docType, formType reflect.Type) (*Plan, error) {
+	for docType.Kind() == reflect.Ptr {
+		docType = docType.Elem()
+	}
+	for formType.Kind() == reflect.Ptr {
+		formType = formType.Elem()
+	}
+	if docType.Kind() != reflect.Struct || formType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: Compile needs struct types, got %s and %s", ErrUnsupportedType, docType, formType)
+	}
+
+	c := &compiler{m: m, seen: make(map[planKey]bool)}
+	c.structPair(docType, formType, "")
+	if err := errors.Join(c.errs...); err != nil {
+		return nil, err
+	}
+
+	p := &Plan{m: m, docType: docType, formType: formType, structs: make(map[string]*compiledStruct)}
+	m.mu.RLock()
+	p.version = m.version
+	m.mu.RUnlock()
+	p.compileStruct(docType, formType, "", make(map[planKey]bool))
+	return p, nil
+}
+
+// Execute maps doc, a pointer to the plan's document type, into form, a
+// pointer to its form type. err is treated as in MapToForm. Once
+// configuration, field mappers or form field types change on the mapper,
+// Execute resolves fields per call like MapToForm; compile again to resolve
+// them up front.
+func (p *Plan) Execute(doc any, err error, form any) error {
+	if t := reflect.TypeOf(doc); t != reflect.PointerTo(p.docType) {
+		return fmt.Errorf("%w: plan maps *%s, got %s", ErrUnsupportedType, p.docType, t)
+	}
+	if t := reflect.TypeOf(form); t != reflect.PointerTo(p.formType) {
+		return fmt.Errorf("%w: plan maps into *%s, got %s", ErrUnsupportedType, p.formType, t)
+	}
+
+	st := &mapState{}
+	p.m.mu.RLock()
+	if p.version == p.m.version {
+		st.compiled = p.structs
+	}
+	p.m.mu.RUnlock()
+	return p.m.mapToForm(doc, err, form, st)
+}
+
+// compileStruct resolves the struct pair at prefix as mapStruct does, then
+// the struct pairs of its fields, following mapField. active holds the pairs
+// being compiled, so recursive types stop at their first repetition.
+func (p *Plan) compileStruct(docType, formType reflect.Type, prefix string, active map[planKey]bool) {
+	key := planKey{docType, formType}
+	if active[key] {
+		return
+	}
+	active[key] = true
+	defer delete(active, key)
+
+	cs := &compiledStruct{docType: docType, formType: formType}
+	for _, plan := range p.m.structPlan(docType, formType) {
+		fieldPath, formFieldName, fc, skip := p.m.resolvePath(plan.tag, pathOf(prefix))
+		if skip {
+			continue
+		}
+		formField, found := p.m.planFormField(plan, formType, formFieldName)
+		if !found {
+			continue
+		}
+
+		fieldMapper, siblingMapper := p.m.fieldMappersFor(fieldPath)
+		cs.fields = append(cs.fields, structField{
+			index:     plan.index,
+			name:      plan.tag.name,
+			fc:        fc,
+			formField: formField,
+			mappers:   &pathMappers{field: fieldMapper, sibling: siblingMapper},
+		})
+		if fieldMapper != nil || siblingMapper != nil {
+			continue
+		}
+
+		if d, f, ok := p.m.nestedStructs(docType.Field(plan.index).Type, formField.Type); ok {
+			p.compileStruct(d, f, fieldPath.String(), active)
+		}
+	}
+	p.structs[prefix] = cs
+}
+
+// nestedStructs returns the struct pair mapField hands to mapStruct for a
+// document field of type docType and a form field of type formType, if any.
+func (m *Mapper) nestedStructs(docType, formType reflect.Type) (reflect.Type, reflect.Type, bool) {
+	for !m.isFormField(formType) {
+		switch {
+		case docType.Kind() == reflect.Ptr && formType.Kind() == reflect.Ptr:
+			docType, formType = docType.Elem(), formType.Elem()
+		case docType.Kind() == reflect.Ptr:
+			docType = docType.Elem()
+		case docType.Kind() == reflect.Struct && formType.Kind() == reflect.Struct:
+			return docType, formType, true
+		default:
+			return nil, nil, false
+		}
+	}
+	return nil, nil, false
+}
+
+type compiler struct {
+	m    *Mapper
+	seen map[planKey]bool
+	errs []error
+}
+
+func (c *compiler) fail(fieldPath string, err error) {
+	c.errs = append(c.errs, &FieldError{Path: fieldPath, Kind: ErrMappingFailed, Err: err})
+}
+
+func (c *compiler) mismatch(fieldPath string, docType, formType reflect.Type) {
+	c.fail(fieldPath, fmt.Errorf("%w: cannot map %s into %s", ErrUnsupportedType, docType, formType))
+}
+
+// structPair follows mapStruct. Recursive types are checked once per pair.
+func (c *compiler) structPair(docType, formType reflect.Type, pathPrefix string) {
+	key := planKey{docType, formType}
+	if c.seen[key] {
+		return
+	}
+	c.seen[key] = true
+
+	for _, plan := range c.m.structPlan(docType, formType) {
+		fieldPath, formFieldName, fc, skip := c.m.resolveTag(plan.tag, pathPrefix)
+		if skip {
+			continue
+		}
+
+		formField, found := c.m.planFormField(plan, formType, formFieldName)
+		if !found || !formField.IsExported() {
+			if formFieldName != docType.Field(plan.index).Name {
+				c.fail(fieldPath, fmt.Errorf("no exported form field %s in %s", formFieldName, formType))
+			}
+			continue
+		}
+
+		if err := checkDurationUnit(fc.DurationUnit); err != nil {
+			c.fail(fieldPath, err)
+		}
+		c.value(docType.Field(plan.index).Type, formField.Type, fieldPath)
+	}
+}
+
+// value follows mapField.
+func (c *compiler) value(docType, formType reflect.Type, fieldPath string) {
+	if fieldMapper, siblingMapper := c.m.fieldMappersFor(pathOf(fieldPath)); fieldMapper != nil || siblingMapper != nil {
+		return
+	}
+	if c.m.isFormField(formType) {
+		return
+	}
+
+	switch {
+	case docType.Kind() == reflect.Interface:
+	case docType.Kind() == reflect.Ptr && formType.Kind() != reflect.Ptr:
+		c.value(docType.Elem(), formType, fieldPath)
+	case isListKind(docType) && isListKind(formType):
+		c.elem(docType.Elem(), formType.Elem(), fieldPath+"[*]")
+	case docType.Kind() == reflect.Map && formType.Kind() == reflect.Map:
+		if k := formType.Key(); docType.Key() != k && k.Kind() != reflect.String {
+			c.fail(fieldPath, fmt.Errorf("%w: cannot use map key of type %s as %s", ErrUnsupportedType, docType.Key(), k))
+			return
+		}
+		c.elem(docType.Elem(), formType.Elem(), fieldPath+"[*]")
+	case docType.Kind() == reflect.Struct && formType.Kind() == reflect.Struct:
+		c.structPair(docType, formType, fieldPath)
+	case docType.Kind() == reflect.Map && formType.Kind() == reflect.Struct:
+	case docType.Kind() == reflect.Ptr && formType.Kind() == reflect.Ptr:
+		c.value(docType.Elem(), formType.Elem(), fieldPath)
+	default:
+		c.mismatch(fieldPath, docType, formType)
+	}
+}
+
+// elem follows mapElemValue.
+func (c *compiler) elem(docType, formType reflect.Type, elemPath string) {
+	if fieldMapper, siblingMapper := c.m.fieldMappersFor(pathOf(elemPath)); fieldMapper != nil || siblingMapper != nil {
+		return
+	}
+	if c.m.isFormField(formType) {
+		return
+	}
+
+	for docType.Kind() == reflect.Ptr {
+		docType = docType.Elem()
+	}
+
+	switch {
+	case docType.Kind() == reflect.Interface:
+	case docType.Kind() == reflect.Struct && formType.Kind() == reflect.Struct:
+		c.structPair(docType, formType, elemPath)
+	case docType.Kind() == reflect.Map && formType.Kind() == reflect.Struct:
+	default:
+		c.mismatch(elemPath, docType, formType)
+	}
+}
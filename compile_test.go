@@ -0,0 +1,218 @@
+package formmap
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestMapper_Compile(t *testing.T) {
+	mapper := NewMapper()
+
+	plan, err := mapper.Compile(reflect.TypeOf(TestDocument{}), reflect.TypeOf(&TestFormData{}))
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+
+	doc := &TestDocument{Name: "Widget", Items: []TestItem{{ItemID: "i1"}}}
+	form := &TestFormData{}
+	valErr := &ValidationError{Errors: Errors{"Name": ValidationField{Tag: "required"}}}
+	if err := plan.Execute(doc, valErr, form); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if form.Name.Value != "Widget" || form.Name.Error == "" || form.Items[0].ItemID.Value != "i1" {
+		t.Errorf("form = %+v", form)
+	}
+
+	if err := plan.Execute(&TestItem{}, nil, form); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("Execute(wrong doc) error = %v, want ErrUnsupportedType", err)
+	}
+	if err := plan.Execute(doc, nil, &TestItemForm{}); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("Execute(wrong form) error = %v, want ErrUnsupportedType", err)
+	}
+}
+
+func TestMapper_Compile_Mismatches(t *testing.T) {
+	type item struct{ Name string }
+	type itemForm struct{ Name FormInputData }
+	type doc struct {
+		Title   string `formmap:"Heading"`
+		Meta    item
+		Items   []*item
+		Counts  map[string]int
+		Missing string
+	}
+	type form struct {
+		Meta   []FormInputData
+		Items  []*itemForm
+		Counts map[int]FormInputData
+	}
+
+	_, err := NewMapper().Compile(reflect.TypeOf(doc{}), reflect.TypeOf(form{}))
+	if !errors.Is(err, ErrMappingFailed) || !errors.Is(err, ErrUnsupportedType) {
+		t.Fatalf("Compile() error = %v, want mapping failures", err)
+	}
+
+	var fe *FieldError
+	if !errors.As(err, &fe) {
+		t.Fatalf("Compile() error = %v, want *FieldError", err)
+	}
+	for _, path := range []string{"Title", "Meta", "Items[*]", "Counts"} {
+		if !strings.Contains(err.Error(), "field "+path+":") {
+			t.Errorf("Compile() error = %v, want failure for %s", err, path)
+		}
+	}
+	if strings.Contains(err.Error(), "Missing") {
+		t.Errorf("Compile() reported unmatched field: %v", err)
+	}
+
+	// A field mapper takes over the mismatched element.
+	mapper := NewMapper()
+	mapper.RegisterFieldMapper("Meta", func(docField, formField reflect.Value, fieldPath string, valErr *ValidationError) error {
+		return nil
+	})
+	_, err = mapper.Compile(reflect.TypeOf(doc{}), reflect.TypeOf(form{}))
+	if strings.Contains(err.Error(), "field Meta:") {
+		t.Errorf("Compile() error = %v, want Meta handled by its field mapper", err)
+	}
+
+	if _, err := mapper.Compile(reflect.TypeOf(""), reflect.TypeOf(form{})); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("Compile(string) error = %v, want ErrUnsupportedType", err)
+	}
+}
+
+func TestMapper_Compile_RecursiveTypes(t *testing.T) {
+	type node struct {
+		Name     string
+		Children []node
+	}
+	type nodeForm struct {
+		Name     FormInputData
+		Children []nodeForm
+	}
+
+	if _, err := NewMapper().Compile(reflect.TypeOf(node{}), reflect.TypeOf(nodeForm{})); err != nil {
+		t.Errorf("Compile() error = %v", err)
+	}
+}
+
+func TestPlan_Execute_Resolved(t *testing.T) {
+	type address struct{ City, Zip string }
+	type addressForm struct{ City, Zip FormInputData }
+	type link struct {
+		Name string
+		Next *link
+	}
+	type linkForm struct {
+		Name FormInputData
+		Next *linkForm
+	}
+	type doc struct {
+		Name     string
+		Billing  address
+		Shipping *address
+		Chain    link
+		Lines    []address
+	}
+	type form struct {
+		Name     FormInputData
+		Billing  addressForm
+		Shipping *addressForm
+		Chain    linkForm
+		Lines    []addressForm
+	}
+
+	mapper := NewMapper()
+	if err := mapper.ApplyConfig(&Config{Fields: map[string]FieldConfig{"Billing.Zip": {Label: "Postcode"}}}); err != nil {
+		t.Fatal(err)
+	}
+	mapper.RegisterPathConverter("Name", func(v reflect.Value) string { return strings.ToUpper(v.String()) })
+
+	plan, err := mapper.Compile(reflect.TypeOf(doc{}), reflect.TypeOf(form{}))
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+
+	// Structs at fixed paths are resolved up front, a recursive type once.
+	for _, path := range []string{"", "Billing", "Shipping", "Chain"} {
+		if plan.structs[path] == nil {
+			t.Errorf("plan has no struct at %q", path)
+		}
+	}
+	for _, path := range []string{"Chain.Next", "Lines[*]"} {
+		if plan.structs[path] != nil {
+			t.Errorf("plan has a struct at %q", path)
+		}
+	}
+
+	d := &doc{
+		Name:     "order",
+		Billing:  address{City: "Cairo", Zip: "11511"},
+		Shipping: &address{City: "Giza"},
+		Chain:    link{Name: "a", Next: &link{Name: "b", Next: &link{Name: "c"}}},
+		Lines:    []address{{City: "Alexandria"}},
+	}
+	valErr := &ValidationError{Errors: Errors{"Billing.Zip": ValidationField{Tag: "required"}}}
+
+	execute := func() *form {
+		t.Helper()
+		f := &form{}
+		if err := plan.Execute(d, valErr, f); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		want := &form{}
+		if err := mapper.MapToForm(d, valErr, want); err != nil {
+			t.Fatalf("MapToForm() error = %v", err)
+		}
+		if !reflect.DeepEqual(f, want) {
+			t.Errorf("Execute() = %+v, MapToForm() = %+v", f, want)
+		}
+		return f
+	}
+
+	f := execute()
+	if f.Name.Value != "ORDER" || f.Billing.Zip.Label != "Postcode" || f.Billing.Zip.Error == "" || f.Chain.Next.Next.Name.Value != "c" {
+		t.Errorf("Execute() = %+v", f)
+	}
+
+	// Registrations after Compile still apply.
+	mapper.RegisterPathConverter("Billing.City", func(v reflect.Value) string { return "city" })
+	if f := execute(); f.Billing.City.Value != "city" {
+		t.Errorf("Billing.City = %q, want the converter registered after Compile", f.Billing.City.Value)
+	}
+}
+
+func TestPlan_Execute_RegisterFormFieldType(t *testing.T) {
+	type money struct{ Amount int }
+	type moneyInput struct {
+		Value, Error string
+		Amount       FormInputData
+	}
+	type doc struct{ Price money }
+	type form struct{ Price moneyInput }
+
+	mapper := NewMapper()
+	RegisterConverterFor(mapper, func(m money) string { return strconv.Itoa(m.Amount) + " EGP" })
+	plan, err := mapper.Compile(reflect.TypeOf(doc{}), reflect.TypeOf(form{}))
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+
+	// Once the form type is a leaf, the plan mustn't walk into it.
+	if err := mapper.RegisterFormFieldType(reflect.TypeOf(moneyInput{})); err != nil {
+		t.Fatal(err)
+	}
+	if plan.version == mapper.version {
+		t.Error("RegisterFormFieldType() left the plan current")
+	}
+	d := &doc{Price: money{Amount: 12}}
+	f := &form{}
+	if err := plan.Execute(d, nil, f); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if f.Price.Value != "12 EGP" || f.Price.Amount.Value != "" {
+		t.Errorf("Price = %+v, want it mapped as a leaf", f.Price)
+	}
+}
@@ -0,0 +1,298 @@
+package formmap
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config describes mapper behaviour that can change without recompiling,
+// keyed by field path. Paths may be patterns such as "Items[*].Price" to
+// apply to every element.
+type Config struct {
+	Fields map[string]FieldConfig `json:"fields" yaml:"fields"`
+}
+
+type FieldConfig struct {
+	// Name is the form struct field the document field maps to.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	Skip bool   `json:"skip,omitempty" yaml:"skip,omitempty"`
+
+	TimeLayout   string `json:"time_layout,omitempty" yaml:"time_layout,omitempty"`
+	Precision    *int   `json:"precision,omitempty" yaml:"precision,omitempty"`
+	DurationUnit string `json:"duration_unit,omitempty" yaml:"duration_unit,omitempty"`
+	// Separator joins a slice mapped into a single form value.
+	Separator string `json:"separator,omitempty" yaml:"separator,omitempty"`
+	// Group writes an integer with the locale's group separator, e.g.
+	// "1.234" under de-DE. Floats and decimals are always grouped; integers
+	// stay plain by default, as years and <input type="number"> values must.
+	Group bool `json:"group,omitempty" yaml:"group,omitempty"`
+
+	// Default fills the form value when the document value renders empty.
+	Default string `json:"default,omitempty" yaml:"default,omitempty"`
+
+	Label       string `json:"label,omitempty" yaml:"label,omitempty"`
+	Placeholder string `json:"placeholder,omitempty" yaml:"placeholder,omitempty"`
+}
+
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		return ParseConfigJSON(data)
+	case ".yaml", ".yml":
+		return ParseConfigYAML(data)
+	default:
+		return nil, fmt.Errorf("unsupported config format %q", ext)
+	}
+}
+
+func ParseConfigJSON(data []byte) (*Config, error) {
+	cfg := &Config{}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parsing JSON config: %w", err)
+	}
+	return cfg, cfg.validate()
+}
+
+func ParseConfigYAML(data []byte) (*Config, error) {
+	cfg := &Config{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parsing YAML config: %w", err)
+	}
+	return cfg, cfg.validate()
+}
+
+func (c *Config) validate() error {
+	for path, fc := range c.Fields {
+		if !validDurationUnit(fc.DurationUnit) {
+			return fmt.Errorf("field %s: unknown duration unit %q", path, fc.DurationUnit)
+		}
+	}
+	return nil
+}
+
+func (m *Mapper) ApplyConfig(cfg *Config) error {
+	if cfg == nil {
+		return nil
+	}
+
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for path, fc := range cfg.Fields {
+		m.fieldConfigs[path] = fc
+	}
+	m.version++
+	return nil
+}
+
+// Label returns the label configured for the field at path, or "" without
+// one. Pass it to playground.WithLabels to name fields by their labels
+// in cross-field errors.
+func (m *Mapper) Label(path string) string {
+	fc, _ := m.fieldConfig(pathOf(path))
+	return fc.Label
+}
+
+func (m *Mapper) fieldConfig(fieldPath pathKey) (FieldConfig, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if len(m.fieldConfigs) == 0 {
+		return FieldConfig{}, false
+	}
+
+	return lookupPathKey(m.fieldConfigs, fieldPath)
+}
+
+func (m *Mapper) elemConfig(fc FieldConfig, elemPath pathKey) FieldConfig {
+	if configured, ok := m.fieldConfig(elemPath); ok {
+		return fc.merge(configured)
+	}
+	return fc
+}
+
+// merge overlays the non-zero settings of other onto fc.
+func (fc FieldConfig) merge(other FieldConfig) FieldConfig {
+	if other.Name != "" {
+		fc.Name = other.Name
+	}
+	if other.Skip {
+		fc.Skip = true
+	}
+	if other.TimeLayout != "" {
+		fc.TimeLayout = other.TimeLayout
+	}
+	if other.Precision != nil {
+		fc.Precision = other.Precision
+	}
+	if other.DurationUnit != "" {
+		fc.DurationUnit = other.DurationUnit
+	}
+	if other.Separator != "" {
+		fc.Separator = other.Separator
+	}
+	if other.Group {
+		fc.Group = true
+	}
+	if other.Default != "" {
+		fc.Default = other.Default
+	}
+	if other.Label != "" {
+		fc.Label = other.Label
+	}
+	if other.Placeholder != "" {
+		fc.Placeholder = other.Placeholder
+	}
+	return fc
+}
+
+func (fc FieldConfig) convert(v reflect.Value) (string, bool, error) {
+	if fc.TimeLayout == "" && fc.DurationUnit == "" && fc.Precision == nil {
+		return "", false, nil
+	}
+
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", false, nil
+		}
+		v = v.Elem()
+	}
+
+	if inner, ok := sqlNullValue(v); ok {
+		v = inner
+	}
+
+	if !v.IsValid() || isZero(v) {
+		return "", false, nil
+	}
+
+	switch val := v.Interface().(type) {
+	case time.Time:
+		if fc.TimeLayout != "" {
+			return val.Format(fc.TimeLayout), true, nil
+		}
+	case time.Duration:
+		if fc.DurationUnit != "" {
+			if err := checkDurationUnit(fc.DurationUnit); err != nil {
+				return "", false, err
+			}
+			return formatDuration(val, fc.DurationUnit), true, nil
+		}
+	}
+
+	if fc.Precision != nil {
+		switch v.Kind() {
+		case reflect.Float32, reflect.Float64:
+			return strconv.FormatFloat(v.Float(), 'f', *fc.Precision, 64), true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+// parse is the inverse of convert for the per-field formats. Times without
+// an offset are read in loc.
+func (fc FieldConfig) parse(s string, v reflect.Value, loc *time.Location) (bool, error) {
+	switch v.Type() {
+	case reflect.TypeOf(time.Time{}):
+		if fc.TimeLayout == "" {
+			return false, nil
+		}
+		t, err := time.ParseInLocation(fc.TimeLayout, s, loc)
+		if err != nil {
+			return true, err
+		}
+		v.Set(reflect.ValueOf(t))
+		return true, nil
+	case reflect.TypeOf(time.Duration(0)):
+		if fc.DurationUnit == "" {
+			return false, nil
+		}
+		if err := checkDurationUnit(fc.DurationUnit); err != nil {
+			return true, err
+		}
+		d, err := parseDuration(s, fc.DurationUnit)
+		if err != nil {
+			return true, err
+		}
+		v.SetInt(int64(d))
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (fc FieldConfig) separator() string {
+	if fc.Separator == "" {
+		return ", "
+	}
+	return fc.Separator
+}
+
+func validDurationUnit(unit string) bool {
+	switch unit {
+	case "", "seconds", "minutes", "hours", "string":
+		return true
+	}
+	return false
+}
+
+// checkDurationUnit rejects a unit, such as one from a `unit=` tag option,
+// that formatDuration and parseDuration don't know.
+func checkDurationUnit(unit string) error {
+	if !validDurationUnit(unit) {
+		return fmt.Errorf("%w: unknown duration unit %q", ErrInvalidOption, unit)
+	}
+	return nil
+}
+
+// formatDuration renders d in unit: whole seconds or minutes, fractional
+// hours, or a Go duration string such as "1h30m0s".
+func formatDuration(d time.Duration, unit string) string {
+	switch unit {
+	case "seconds":
+		return strconv.Itoa(int(d.Seconds()))
+	case "hours":
+		return strconv.FormatFloat(d.Hours(), 'f', -1, 64)
+	case "string":
+		return d.String()
+	default:
+		return strconv.Itoa(int(d.Minutes()))
+	}
+}
+
+func parseDuration(s, unit string) (time.Duration, error) {
+	var scale time.Duration
+	switch unit {
+	case "seconds":
+		scale = time.Second
+	case "hours":
+		scale = time.Hour
+	case "string":
+		return time.ParseDuration(s)
+	default:
+		scale = time.Minute
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(n * float64(scale)), nil
+}
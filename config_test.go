@@ -0,0 +1,141 @@
+package formmap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type TestRenamedForm struct {
+	Title     FormInputData
+	Price     FormInputData
+	CreatedAt FormInputData
+	Duration  FormInputData
+	Items     []TestItemForm
+}
+
+const testConfigYAML = `
+fields:
+  Name:
+    name: Title
+    label: Product name
+    placeholder: e.g. Coffee mug
+  Price:
+    precision: 2
+  CreatedAt:
+    time_layout: "2006-01-02"
+  Duration:
+    duration_unit: hours
+  Items[*].Price:
+    precision: 1
+  Items[*].ItemName:
+    skip: true
+`
+
+const testConfigJSON = `{
+  "fields": {
+    "Name": {"name": "Title", "label": "Product name", "placeholder": "e.g. Coffee mug"},
+    "Price": {"precision": 2},
+    "CreatedAt": {"time_layout": "2006-01-02"},
+    "Duration": {"duration_unit": "hours"},
+    "Items[*].Price": {"precision": 1},
+    "Items[*].ItemName": {"skip": true}
+  }
+}`
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"mapper.yaml": testConfigYAML,
+		"mapper.json": testConfigJSON,
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := LoadConfig(path)
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+
+			mapper := NewMapper()
+			if err := mapper.ApplyConfig(cfg); err != nil {
+				t.Fatalf("ApplyConfig() error = %v", err)
+			}
+
+			doc := &TestDocument{
+				Name:      "Mug",
+				Price:     9.5,
+				CreatedAt: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+				Duration:  90 * time.Minute,
+				Items:     []TestItem{{ItemName: "Handle", Price: 2}},
+			}
+			valErr := &ValidationError{Errors: Errors{"Name": ValidationField{Tag: "required"}}}
+			form := &TestRenamedForm{}
+
+			if err := mapper.MapToForm(doc, valErr, form); err != nil {
+				t.Fatalf("MapToForm() error = %v", err)
+			}
+
+			want := FormInputData{
+				Value:       "Mug",
+				Error:       "This field is required",
+				Label:       "Product name",
+				Placeholder: "e.g. Coffee mug",
+			}
+			if form.Title != want {
+				t.Errorf("Title = %+v, want %+v", form.Title, want)
+			}
+			if form.Price.Value != "9.50" {
+				t.Errorf("Price = %q, want 9.50", form.Price.Value)
+			}
+			if form.CreatedAt.Value != "2024-03-01" {
+				t.Errorf("CreatedAt = %q, want 2024-03-01", form.CreatedAt.Value)
+			}
+			if form.Duration.Value != "1.5" {
+				t.Errorf("Duration = %q, want 1.5", form.Duration.Value)
+			}
+			if form.Items[0].Price.Value != "2.0" {
+				t.Errorf("Items[0].Price = %q, want 2.0", form.Items[0].Price.Value)
+			}
+			if form.Items[0].ItemName.Value != "" {
+				t.Errorf("Items[0].ItemName = %q, want skipped", form.Items[0].ItemName.Value)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"mapper.toml", "fields = {}"},
+		{"bad.json", "{"},
+		{"unit.yaml", "fields:\n  Duration:\n    duration_unit: weeks\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadConfig(path); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+
+	if _, err := LoadConfig(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("expected error for missing file, got nil")
+	}
+}
@@ -0,0 +1,22 @@
+package formmap
+
+import (
+	"reflect"
+)
+
+// RegisterDecimal registers format and parse for a decimal type such as
+// shopspring's decimal.Decimal without formmap depending on it:
+//
+//	formmap.RegisterDecimal(mapper, decimal.Decimal.String, decimal.NewFromString)
+//
+// format must return a plain number like "-1234.50". Unlike other registered
+// converters, its output follows the mapper's locale, and localized input is
+// normalized before parse sees it.
+func RegisterDecimal[T any](m *Mapper, format func(T) string, parse func(string) (T, error)) {
+	RegisterConverterFor(m, format)
+	RegisterParserFor(m, parse)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.localizable[reflect.TypeFor[T]()] = true
+}
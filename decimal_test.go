@@ -0,0 +1,49 @@
+package formmap
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+// testDecimal stands in for third-party decimal types.
+type testDecimal struct{ r string }
+
+func (d testDecimal) String() string { return d.r }
+
+func parseTestDecimal(s string) (testDecimal, error) {
+	if _, ok := new(big.Rat).SetString(s); !ok {
+		return testDecimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	return testDecimal{r: s}, nil
+}
+
+func TestRegisterDecimal(t *testing.T) {
+	type invoice struct{ Total testDecimal }
+	type invoiceForm struct{ Total FormInputData }
+
+	mapper := NewMapper(WithLocale("de-DE"))
+	RegisterDecimal(mapper, testDecimal.String, parseTestDecimal)
+
+	d := &invoice{Total: testDecimal{r: "1234567.50"}}
+	f := &invoiceForm{}
+	if err := mapper.MapToForm(d, nil, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+	if f.Total.Value != "1.234.567,50" {
+		t.Errorf("Total = %q, want 1.234.567,50", f.Total.Value)
+	}
+
+	back := &invoice{}
+	if err := mapper.MapFromForm(f, back); err != nil {
+		t.Fatalf("MapFromForm() error = %v", err)
+	}
+	if back.Total != d.Total {
+		t.Errorf("Total = %v, want %v", back.Total, d.Total)
+	}
+
+	f.Total.Value = "abc"
+	if err := mapper.MapFromForm(f, back); err == nil {
+		t.Error("expected parse error")
+	}
+}
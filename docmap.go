@@ -0,0 +1,112 @@
+package formmap
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// mapDocMap maps a document held in a string-keyed map, such as the
+// map[string]any a JSON decode produces, into a form struct. Keys are
+// matched to form fields by Go name, then by `json` tag, then by Go name
+// ignoring case, then through the name transformer; error paths use the keys
+// as they are.
+func (m *Mapper) mapDocMap(docMap, formVal reflect.Value, st *mapState, pathPrefix pathKey) error {
+	if docMap.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("%w: document map keys must be strings, got %s", ErrUnsupportedType, docMap.Type().Key())
+	}
+	if err := st.limits.checkLen(docMap.Len()); err != nil {
+		return err
+	}
+	if err := st.limits.enter(pathPrefix); err != nil {
+		return err
+	}
+	defer st.limits.leave()
+
+	keys := docMap.MapKeys()
+	slices.SortFunc(keys, func(a, b reflect.Value) int { return strings.Compare(a.String(), b.String()) })
+
+	for _, key := range keys {
+		fieldPath := pathPrefix.field(key.String())
+
+		fc, _ := m.fieldConfig(fieldPath)
+		formFieldName := key.String()
+		if fc.Name != "" {
+			formFieldName = fc.Name
+		}
+
+		if fc.Skip {
+			st.reportSkip(fieldPath, "skipped by config")
+			continue
+		}
+		if reason, skipped := st.skipsPath(fieldPath); skipped {
+			st.reportSkip(fieldPath, reason)
+			continue
+		}
+		if target, ok := st.fieldAliases[string(fieldPath.bytes())]; ok {
+			st.reportSkipf(fieldPath, "mapped to %s by MapOptions.FieldAliases", target)
+			continue
+		}
+
+		formField, found := findKeyField(formVal.Type(), formFieldName)
+		if !found {
+			formField, found = m.findFormField(formVal.Type(), formFieldName)
+		}
+		if !found {
+			st.reportSkipf(fieldPath, "no form field %s", formFieldName)
+			continue
+		}
+
+		formFieldVal := formVal.FieldByIndex(formField.Index)
+		if !formFieldVal.CanSet() {
+			st.reportSkipf(fieldPath, "form field %s is not settable", formField.Name)
+			continue
+		}
+		if err := st.limits.visit(); err != nil {
+			return fieldError(fieldPath.String(), ErrMappingFailed, err)
+		}
+
+		docFieldVal := docMap.MapIndex(key)
+		err := m.withFieldHooks(fieldPath, docFieldVal, formFieldVal, func() error {
+			if handled, err := m.applyFieldMapper(st, docMap, docFieldVal, formFieldVal, fieldPath); handled {
+				return err
+			}
+
+			if err := m.mapField(docMap, docFieldVal, formFieldVal, st, fieldPath, fc, formField); err != nil {
+				return fieldError(fieldPath.String(), ErrMappingFailed, err)
+			}
+			return nil
+		})
+		if err != nil {
+			if err := st.fail(err); err != nil {
+				return err
+			}
+		}
+	}
+
+	return m.mapStructErrors(formVal, st, pathPrefix)
+}
+
+// findKeyField finds the exported form field a document map key refers to.
+func findKeyField(formType reflect.Type, key string) (reflect.StructField, bool) {
+	if sf, ok := formType.FieldByName(key); ok && sf.IsExported() {
+		return sf, true
+	}
+
+	matches := []func(sf reflect.StructField) bool{
+		func(sf reflect.StructField) bool {
+			name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+			return name == key
+		},
+		func(sf reflect.StructField) bool { return strings.EqualFold(sf.Name, key) },
+	}
+	for _, match := range matches {
+		for i := 0; i < formType.NumField(); i++ {
+			if sf := formType.Field(i); sf.IsExported() && match(sf) {
+				return sf, true
+			}
+		}
+	}
+	return reflect.StructField{}, false
+}
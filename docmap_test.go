@@ -0,0 +1,114 @@
+package formmap
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestMapper_MapToForm_MapDocument(t *testing.T) {
+	type addressForm struct {
+		City FormInputData
+	}
+	type itemForm struct {
+		SKU FormInputData `json:"sku"`
+		Qty FormInputData
+	}
+	type orderForm struct {
+		Name    FormInputData
+		Total   FormInputData
+		Paid    FormInputData
+		Note    FormInputData
+		Address addressForm
+		Items   []itemForm
+	}
+
+	var doc map[string]any
+	raw := `{
+		"Name": "Ada",
+		"total": 12.5,
+		"paid": true,
+		"note": null,
+		"Address": {"City": "Cairo"},
+		"Items": [{"sku": "A-1", "qty": 2}, {"sku": "B-2", "qty": 1}],
+		"Extra": "ignored"
+	}`
+	if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	valErr := &ValidationError{Errors: Errors{
+		"Items[1].qty": ValidationField{Tag: "required"},
+	}}
+
+	form := &orderForm{Note: FormInputData{Value: "stale"}}
+	if err := NewMapper().MapToForm(doc, valErr, form); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	checks := map[string]string{
+		"Name":               form.Name.Value,
+		"Total":              form.Total.Value,
+		"Paid":               form.Paid.Value,
+		"Note":               form.Note.Value,
+		"Address.City":       form.Address.City.Value,
+		"Items[0].SKU":       form.Items[0].SKU.Value,
+		"Items[1].Qty":       form.Items[1].Qty.Value,
+		"Items[1].Qty.Error": form.Items[1].Qty.Error,
+	}
+	want := map[string]string{
+		"Name":               "Ada",
+		"Total":              "12.5",
+		"Paid":               "true",
+		"Note":               "",
+		"Address.City":       "Cairo",
+		"Items[0].SKU":       "A-1",
+		"Items[1].Qty":       "1",
+		"Items[1].Qty.Error": "This field is required",
+	}
+	for key, got := range checks {
+		if got != want[key] {
+			t.Errorf("%s = %q, want %q", key, got, want[key])
+		}
+	}
+
+	// A pointer to the map works too.
+	form = &orderForm{}
+	if err := NewMapper().MapToForm(&doc, nil, form); err != nil {
+		t.Fatalf("MapToForm(&doc) error = %v", err)
+	}
+	if form.Name.Value != "Ada" {
+		t.Errorf("Name = %q, want Ada", form.Name.Value)
+	}
+}
+
+func TestMapper_MapToForm_MapDocumentOptions(t *testing.T) {
+	type form struct {
+		Title  FormInputData
+		Author FormInputData
+		Secret FormInputData
+	}
+
+	doc := map[string]any{
+		"Title":  "Go",
+		"Meta":   map[string]any{"Author": "Rob"},
+		"Secret": "hunter2",
+	}
+
+	f := &form{}
+	err := NewMapper().MapToFormWithOptions(doc, nil, f, MapOptions{
+		SkipFields:   []string{"Secret"},
+		FieldAliases: map[string]string{"Meta.Author": "Author"},
+	})
+	if err != nil {
+		t.Fatalf("MapToFormWithOptions() error = %v", err)
+	}
+	if f.Title.Value != "Go" || f.Author.Value != "Rob" || f.Secret.Value != "" {
+		t.Errorf("form = %+v", f)
+	}
+
+	err = NewMapper().MapToForm(map[int]any{1: "x"}, nil, f)
+	if !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("int-keyed map error = %v, want ErrUnsupportedType", err)
+	}
+}
@@ -0,0 +1,49 @@
+package formmap
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// RegisterEnum renders values of T with their labels, e.g.
+// map[Status]string{Pending: "Pending", Shipped: "Shipped"}, and parses the
+// labels back in MapFromForm. A labelled zero value is rendered too, so
+// iota-based enums starting at 0 show their first label.
+func RegisterEnum[T comparable](m *Mapper, labels map[T]string) {
+	t := reflect.TypeFor[T]()
+
+	values := make(map[string]T, len(labels))
+	for value, label := range labels {
+		values[label] = value
+	}
+
+	m.RegisterConverter(t, func(v reflect.Value) string {
+		value := v.Interface().(T)
+		if label, ok := labels[value]; ok {
+			return label
+		}
+		if v.IsZero() {
+			return ""
+		}
+		return fmt.Sprint(value)
+	})
+
+	m.RegisterParser(t, func(s string, v reflect.Value) error {
+		value, ok := values[s]
+		if !ok {
+			return fmt.Errorf("unknown %s %q", t, s)
+		}
+		v.Set(reflect.ValueOf(&value).Elem())
+		return nil
+	})
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.enums[t] = true
+}
+
+func (m *Mapper) isEnum(t reflect.Type) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.enums[t]
+}
@@ -0,0 +1,83 @@
+package formmap
+
+import "testing"
+
+type orderStatus int
+
+const (
+	statusPending orderStatus = iota
+	statusShipped
+	statusDelivered
+)
+
+func TestRegisterEnum(t *testing.T) {
+	type order struct {
+		Status orderStatus
+		Past   []orderStatus
+	}
+	type orderForm struct {
+		Status FormInputData
+		Past   []FormInputData
+	}
+
+	mapper := NewMapper()
+	RegisterEnum(mapper, map[orderStatus]string{
+		statusPending: "Pending",
+		statusShipped: "Shipped",
+	})
+
+	d := &order{Status: statusPending, Past: []orderStatus{statusShipped, statusDelivered}}
+	f := &orderForm{}
+	if err := mapper.MapToForm(d, nil, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	if f.Status.Value != "Pending" {
+		t.Errorf("Status = %q, want Pending", f.Status.Value)
+	}
+	if f.Past[0].Value != "Shipped" {
+		t.Errorf("Past[0] = %q, want Shipped", f.Past[0].Value)
+	}
+	if f.Past[1].Value != "2" {
+		t.Errorf("Past[1] = %q, want unlabelled value 2", f.Past[1].Value)
+	}
+
+	back := &order{Status: statusDelivered}
+	f.Past = f.Past[:1]
+	if err := mapper.MapFromForm(f, back); err != nil {
+		t.Fatalf("MapFromForm() error = %v", err)
+	}
+	if back.Status != statusPending || len(back.Past) != 1 || back.Past[0] != statusShipped {
+		t.Errorf("MapFromForm() = %+v", back)
+	}
+
+	f.Status.Value = "Lost"
+	if err := mapper.MapFromForm(f, back); err == nil {
+		t.Error("expected error for unknown label")
+	}
+}
+
+func TestRegisterEnum_String(t *testing.T) {
+	type role string
+	type user struct{ Role role }
+	type userForm struct{ Role FormInputData }
+
+	mapper := NewMapper()
+	RegisterEnum(mapper, map[role]string{"admin": "Administrator"})
+
+	f := &userForm{}
+	if err := mapper.MapToForm(&user{Role: "admin"}, nil, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+	if f.Role.Value != "Administrator" {
+		t.Errorf("Role = %q, want Administrator", f.Role.Value)
+	}
+
+	// The zero value has no label, so it stays empty.
+	if err := mapper.MapToForm(&user{}, nil, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+	if f.Role.Value != "" {
+		t.Errorf("Role = %q, want empty", f.Role.Value)
+	}
+}
@@ -0,0 +1,52 @@
+package formmap
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNotPointer         = errors.New("not a pointer")
+	ErrNilInput           = errors.New("nil input")
+	ErrNotStruct          = errors.New("not a struct")
+	ErrNotValidationError = errors.New("expected ValidationError")
+	ErrInvalidOption      = errors.New("invalid option")
+	ErrUnsupportedType    = errors.New("unsupported type")
+	ErrLimitExceeded      = errors.New("limit exceeded")
+
+	ErrMappingFailed     = errors.New("mapping failed")
+	ErrFieldMapperFailed = errors.New("custom mapper failed")
+	ErrHookFailed        = errors.New("hook failed")
+	ErrParseFailed       = errors.New("parsing failed")
+)
+
+// FieldError ties a failure to the field path it happened at. Kind is one of
+// the Err*Failed sentinels; errors.Is matches both Kind and the cause:
+//
+//	var fe *formmap.FieldError
+//	if errors.Is(err, formmap.ErrFieldMapperFailed) && errors.As(err, &fe) {
+//		log.Printf("mapper for %s: %v", fe.Path, fe.Err)
+//	}
+type FieldError struct {
+	Path string
+	Kind error
+	Err  error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("field %s: %v: %v", e.Path, e.Kind, e.Err)
+}
+
+func (e *FieldError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// fieldError wraps err for fieldPath unless it already carries a path from a
+// deeper field.
+func fieldError(fieldPath string, kind, err error) error {
+	var fe *FieldError
+	if errors.As(err, &fe) {
+		return err
+	}
+	return &FieldError{Path: fieldPath, Kind: kind, Err: err}
+}
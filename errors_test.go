@@ -0,0 +1,61 @@
+package formmap
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	type doc struct {
+		Name string
+		Age  int
+	}
+	type form struct {
+		Name FormInputData
+		Age  FormInputData
+	}
+
+	errBoom := errors.New("boom")
+	mapper := NewMapper()
+	mapper.RegisterFieldMapper("Name", func(docField, formField reflect.Value, fieldPath string, valErr *ValidationError) error {
+		return errBoom
+	})
+
+	tests := []struct {
+		name     string
+		err      error
+		want     error
+		wantPath string
+	}{
+		{"not pointer", mapper.MapToForm(doc{}, nil, &form{}), ErrNotPointer, ""},
+		{"nil input", mapper.MapToForm((*doc)(nil), nil, &form{}), ErrNilInput, ""},
+		{"wrong error type", mapper.MapToForm(&doc{}, errBoom, &form{}), ErrNotValidationError, ""},
+		{"invalid option", NewMapper(WithLocale("xx")).MapToForm(&doc{}, nil, &form{}), ErrInvalidOption, ""},
+		{"field mapper", mapper.MapToForm(&doc{}, nil, &form{}), ErrFieldMapperFailed, "Name"},
+		{"parse", NewMapper().MapFromForm(&form{Age: FormInputData{Value: "x"}}, &doc{}), ErrParseFailed, "Age"},
+		{"reverse not pointer", NewMapper().MapFromForm(form{}, &doc{}), ErrNotPointer, ""},
+		{"reset nil", ResetForm((*form)(nil)), ErrNilInput, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !errors.Is(tt.err, tt.want) {
+				t.Fatalf("error = %v, want %v", tt.err, tt.want)
+			}
+
+			var fe *FieldError
+			if got := errors.As(tt.err, &fe); got != (tt.wantPath != "") {
+				t.Fatalf("errors.As(FieldError) = %v for %v", got, tt.err)
+			}
+			if fe != nil && fe.Path != tt.wantPath {
+				t.Errorf("Path = %q, want %q", fe.Path, tt.wantPath)
+			}
+		})
+	}
+
+	err := mapper.MapToForm(&doc{}, nil, &form{})
+	if !errors.Is(err, errBoom) {
+		t.Errorf("error %v does not wrap the mapper's cause", err)
+	}
+}
@@ -0,0 +1,68 @@
+package formmap
+
+import (
+	"html"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValueFilter post-processes a converted form value.
+type ValueFilter func(s string) string
+
+// Chain runs filters left to right.
+func Chain(filters ...ValueFilter) ValueFilter {
+	return func(s string) string {
+		for _, f := range filters {
+			s = f(s)
+		}
+		return s
+	}
+}
+
+func TrimSpace(s string) string {
+	return strings.TrimSpace(s)
+}
+
+func HTMLEscape(s string) string {
+	return html.EscapeString(s)
+}
+
+// Truncate cuts values to at most n runes.
+func Truncate(n int) ValueFilter {
+	return func(s string) string {
+		if utf8.RuneCountInString(s) <= n {
+			return s
+		}
+		return string([]rune(s)[:n])
+	}
+}
+
+// RegisterFilter runs filter on every value of type t after it has been
+// converted, whichever converter produced it. Filters registered for the same
+// type run in registration order.
+func (m *Mapper) RegisterFilter(t reflect.Type, filter ValueFilter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.filters[t] = append(m.filters[t], filter)
+}
+
+func (m *Mapper) filterValue(v reflect.Value, s string) string {
+	if !v.IsValid() {
+		return s
+	}
+
+	t := v.Type()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	m.mu.RLock()
+	filters := m.filters[t]
+	m.mu.RUnlock()
+
+	for _, f := range filters {
+		s = f(s)
+	}
+	return s
+}
@@ -0,0 +1,66 @@
+package formmap
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestChain(t *testing.T) {
+	f := Chain(TrimSpace, Truncate(5), HTMLEscape)
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"  hello world ", "hello"},
+		{"<b>bold</b>", "&lt;b&gt;bo"},
+		{"héllo wörld", "héllo"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := f(tt.in); got != tt.want {
+			t.Errorf("Chain()(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMapper_RegisterFilter(t *testing.T) {
+	type post struct {
+		Title string
+		Tags  []string
+		Note  *string
+		Views int
+	}
+	type postForm struct {
+		Title FormInputData
+		Tags  []FormInputData
+		Note  FormInputData
+		Views FormInputData
+	}
+
+	mapper := NewMapper()
+	mapper.RegisterFilter(reflect.TypeOf(""), Chain(TrimSpace, HTMLEscape))
+	mapper.RegisterFilter(reflect.TypeOf(""), strings.ToUpper)
+
+	note := " <i>note</i> "
+	d := &post{Title: "  Tom & Jerry ", Tags: []string{" a "}, Note: &note, Views: 10}
+	f := &postForm{}
+	if err := mapper.MapToForm(d, nil, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	if f.Title.Value != "TOM &AMP; JERRY" {
+		t.Errorf("Title = %q", f.Title.Value)
+	}
+	if f.Tags[0].Value != "A" {
+		t.Errorf("Tags[0] = %q, want A", f.Tags[0].Value)
+	}
+	if f.Note.Value != "&LT;I&GT;NOTE&LT;/I&GT;" {
+		t.Errorf("Note = %q", f.Note.Value)
+	}
+	if f.Views.Value != "10" {
+		t.Errorf("Views = %q, want 10", f.Views.Value)
+	}
+}
@@ -1,177 +1,985 @@
 package formmap
 
 import (
+	"encoding"
+	"errors"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
 type FormInputData struct {
-	Value string
-	Error string
+	Value       string
+	Error       string
+	Label       string
+	Placeholder string
 }
 
+// FormField is a leaf form value. Any form struct field whose type (or a
+// pointer to it) implements FormField receives a converted value and error
+// message instead of being mapped field by field, so projects can use richer
+// input types than FormInputData. Struct types with Label and Placeholder
+// string fields also receive configured labels and placeholders.
+type FormField interface {
+	FormValue() string
+	SetValue(value string)
+	SetError(msg string)
+}
+
+func (f FormInputData) FormValue() string { return f.Value }
+
+func (f *FormInputData) SetValue(value string) { f.Value = value }
+
+func (f *FormInputData) SetError(msg string) { f.Error = msg }
+
+// ErrorsField is implemented by form fields that show every error of their
+// path rather than just the first.
+type ErrorsField interface {
+	SetErrors(msgs []string)
+}
+
+// setFieldErrors sets the first error message of path on ff, and all of them
+// if ff takes them.
+func setFieldErrors(ff FormField, valErr *ValidationError, path string) {
+	ff.SetError(valErr.MsgFor(path))
+	if ef, ok := ff.(ErrorsField); ok {
+		ef.SetErrors(valErr.MsgsFor(path))
+	}
+}
+
+var formFieldType = reflect.TypeOf((*FormField)(nil)).Elem()
+
+// isFormField reports whether t is a leaf form type. Pointers are not leaves
+// themselves; mapping allocates and follows them.
+func isFormField(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr || t.Kind() == reflect.Interface {
+		return false
+	}
+	return t.Implements(formFieldType) || reflect.PointerTo(t).Implements(formFieldType)
+}
+
+// RegisterFormFieldType treats t, a struct with string Value and Error
+// fields, as a leaf form type even though it doesn't implement FormField,
+// e.g. an input type from another package.
+func (m *Mapper) RegisterFormFieldType(t reflect.Type) error {
+	if !hasFormFieldShape(t) {
+		return fmt.Errorf("%s needs string Value and Error fields", t)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.formFieldTypes[t] = true
+	m.version++
+	return nil
+}
+
+func (m *Mapper) isFormField(t reflect.Type) bool {
+	if isFormField(t) {
+		return true
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.formFieldTypes[t]
+}
+
+func hasFormFieldShape(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for _, name := range []string{"Value", "Error"} {
+		f, ok := t.FieldByName(name)
+		if !ok || f.Type.Kind() != reflect.String || !f.IsExported() {
+			return false
+		}
+	}
+	return true
+}
+
+func asFormField(v reflect.Value) (FormField, bool) {
+	if v.CanAddr() {
+		if ff, ok := v.Addr().Interface().(FormField); ok {
+			return ff, true
+		}
+	}
+	if ff, ok := v.Interface().(FormField); ok {
+		return ff, true
+	}
+	if hasFormFieldShape(v.Type()) && v.CanSet() {
+		return structFormField{v}, true
+	}
+	return nil, false
+}
+
+// structFormField adapts a struct with Value and Error fields to FormField.
+type structFormField struct {
+	v reflect.Value
+}
+
+func (f structFormField) FormValue() string { return f.v.FieldByName("Value").String() }
+
+func (f structFormField) SetValue(value string) { f.v.FieldByName("Value").SetString(value) }
+
+func (f structFormField) SetError(msg string) { f.v.FieldByName("Error").SetString(msg) }
+
+// SetErrors fills an Errors []string field if the struct has one.
+func (f structFormField) SetErrors(msgs []string) {
+	if errs := f.v.FieldByName("Errors"); errs.IsValid() && errs.Type() == reflect.TypeOf(msgs) {
+		errs.Set(reflect.ValueOf(msgs))
+	}
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	stringerType      = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
 type ValueConverter func(v reflect.Value) string
 
 type FieldMapper func(docField reflect.Value, formField reflect.Value, fieldPath string, valErr *ValidationError) error
 
+type SiblingFieldMapper func(docParent reflect.Value, docField reflect.Value, formField reflect.Value, fieldPath string, valErr *ValidationError) error
+
+type ConverterCondition func(v reflect.Value, docParent reflect.Value) bool
+
+type conditionalConverter struct {
+	condition ConverterCondition
+	converter ValueConverter
+}
+
+// Mapper is safe for concurrent use. Registrations may happen while other
+// goroutines are mapping; each mapping sees the registries as they are when a
+// given field is looked up.
 type Mapper struct {
-	converters   map[reflect.Type]ValueConverter
-	fieldMappers map[string]FieldMapper
+	mu sync.RWMutex
+
+	converters            map[reflect.Type]ValueConverter
+	conditionalConverters map[reflect.Type][]conditionalConverter
+	fieldMappers          map[string]FieldMapper
+	siblingMappers        map[string]SiblingFieldMapper
+	fieldConfigs          map[string]FieldConfig
+	parsers               map[reflect.Type]ValueParser
+	localizable           map[reflect.Type]bool
+	enums                 map[reflect.Type]bool
+	filters               map[reflect.Type][]ValueFilter
+	formFieldTypes        map[reflect.Type]bool
+	jsonNames             bool
+	nameTransformer       NameTransformer
+	locale                string
+	durationUnit          string
+	bytesEncoding         string
+	location              *time.Location
+	timeLayout            string
+	mergeSlices           bool
+	collectErrors         bool
+	strictConversion      bool
+	globalErrorsField     string
+	structErrorsField     string
+	validateTag           string
+	version               uint64
+	plans                 sync.Map
+	beforeField           []FieldHook
+	afterField            []FieldHook
+	beforeMap             []MapHook
+	afterMap              []MapHook
+}
+
+type MapperOption func(*Mapper)
+
+// WithJSONNames makes field paths follow `json` tags instead of Go field
+// names, so they match error keys produced by JSON-aware validators. Form
+// fields are still looked up by the document field's Go name.
+func WithJSONNames() MapperOption {
+	return func(m *Mapper) {
+		m.jsonNames = true
+	}
 }
 
-func NewMapper() *Mapper {
-	m := &Mapper{
-		converters:   make(map[reflect.Type]ValueConverter),
-		fieldMappers: make(map[string]FieldMapper),
+// WithLocale formats numbers with the separators of the given locale tag,
+// e.g. "de-DE" renders 1234.5 as "1.234,5". MapFromForm accepts the same
+// format back.
+func WithLocale(tag string) MapperOption {
+	return func(m *Mapper) {
+		m.locale = tag
 	}
+}
 
-	m.RegisterConverter(reflect.TypeOf(time.Duration(0)), func(v reflect.Value) string {
-		d := v.Interface().(time.Duration)
-		return strconv.Itoa(int(d.Minutes()))
-	})
+// WithDurationUnit sets how time.Duration fields are written and read back:
+// "seconds", "minutes" (the default), "hours" or "string" for Go duration
+// strings such as "1h30m0s". The `unit=` tag option overrides it per field.
+func WithDurationUnit(unit string) MapperOption {
+	return func(m *Mapper) {
+		m.durationUnit = unit
+	}
+}
 
-	m.RegisterConverter(reflect.TypeOf(time.Time{}), func(v reflect.Value) string {
-		t := v.Interface().(time.Time)
-		if t.IsZero() {
-			return ""
-		}
-		return t.Format(time.RFC3339)
-	})
+// WithTimeZone shows time.Time values in loc. MapFromForm reads times without
+// an offset as wall-clock times in loc, keeps the offset of those with one,
+// and stores them in UTC. Parsers registered for time.Time get no zone.
+func WithTimeZone(loc *time.Location) MapperOption {
+	return func(m *Mapper) {
+		m.location = loc
+	}
+}
 
-	m.RegisterConverter(reflect.TypeOf(float64(0)), func(v reflect.Value) string {
-		f := v.Float()
-		return strconv.FormatFloat(f, 'f', -1, 64)
-	})
+// WithTimeLayout sets the layout time.Time values are written in, instead of
+// RFC 3339. MapFromForm tries it first when parsing times.
+func WithTimeLayout(layout string) MapperOption {
+	return func(m *Mapper) {
+		m.timeLayout = layout
+	}
+}
 
-	m.RegisterConverter(reflect.TypeOf(float32(0)), func(v reflect.Value) string {
-		f := v.Float()
-		return strconv.FormatFloat(f, 'f', -1, 32)
-	})
+// WithSliceMerge keeps existing form slice elements when a slice is resized,
+// so data the caller pre-set in element forms (UI flags, hidden fields)
+// survives where indices overlap. Without it, a form slice already as long as
+// the document keeps its elements, and a resized one starts fresh, whatever
+// its capacity.
+func WithSliceMerge() MapperOption {
+	return func(m *Mapper) {
+		m.mergeSlices = true
+	}
+}
 
-	m.RegisterConverter(reflect.TypeOf(int(0)), func(v reflect.Value) string {
-		return strconv.Itoa(int(v.Int()))
-	})
+// WithCollectErrors keeps mapping after a field fails and returns every
+// failure at the end, joined with errors.Join, instead of stopping at the
+// first one.
+func WithCollectErrors() MapperOption {
+	return func(m *Mapper) {
+		m.collectErrors = true
+	}
+}
 
-	m.RegisterConverter(reflect.TypeOf(int64(0)), func(v reflect.Value) string {
-		return strconv.FormatInt(v.Int(), 10)
-	})
+// WithGlobalErrorsField names the top-level form field that receives the
+// errors not tied to a field, those added with AddGlobal. The field may be a
+// []string for every message, a string for the first one, or a leaf form
+// type. Forms without the field are mapped as usual.
+func WithGlobalErrorsField(name string) MapperOption {
+	return func(m *Mapper) {
+		m.globalErrorsField = name
+	}
+}
 
-	m.RegisterConverter(reflect.TypeOf(bool(false)), func(v reflect.Value) string {
-		return strconv.FormatBool(v.Bool())
-	})
+// WithStructErrorsField names the field of nested form structs that receives
+// the errors of the struct as a whole, such as "Items[2]" for a duplicate line
+// item, rather than of one of its fields. It takes the same types as
+// WithGlobalErrorsField; form structs without the field are mapped as usual.
+func WithStructErrorsField(name string) MapperOption {
+	return func(m *Mapper) {
+		m.structErrorsField = name
+	}
+}
+
+// WithStrictConversion makes mapping fail, naming the field path and type,
+// when a value has no converter and would otherwise be rendered with
+// fmt.Sprint, e.g. a struct or map mapped onto a single input.
+func WithStrictConversion() MapperOption {
+	return func(m *Mapper) {
+		m.strictConversion = true
+	}
+}
+
+// WithValidateTag reads the collection limits of FormSliceMeta from the given
+// struct tag instead of `validate`, to match playground.WithTagName.
+func WithValidateTag(name string) MapperOption {
+	return func(m *Mapper) {
+		m.validateTag = name
+	}
+}
+
+func NewMapper(opts ...MapperOption) *Mapper {
+	m := &Mapper{}
+	converters, localizable := m.defaultConverters()
+	m.resetRegistries(converters, localizable, m.defaultParsers())
+
+	for _, opt := range opts {
+		opt(m)
+	}
 
 	return m
 }
 
+// ResetDefaults drops every registration made since NewMapper: converters,
+// parsers, field mappers, field configs, filters, enums, form field types and
+// hooks. Options passed to NewMapper are kept. The registries are swapped in
+// one step, so mappings running meanwhile see either the old or the new ones.
+func (m *Mapper) ResetDefaults() {
+	converters, localizable := m.defaultConverters()
+	parsers := m.defaultParsers()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.resetRegistries(converters, localizable, parsers)
+	m.plans.Clear()
+}
+
+// resetRegistries replaces every registry with empty ones holding only the
+// given built-ins. Callers hold m.mu or own m.
+func (m *Mapper) resetRegistries(converters map[reflect.Type]ValueConverter, localizable map[reflect.Type]bool, parsers map[reflect.Type]ValueParser) {
+	m.converters = converters
+	m.conditionalConverters = make(map[reflect.Type][]conditionalConverter)
+	m.fieldMappers = make(map[string]FieldMapper)
+	m.siblingMappers = make(map[string]SiblingFieldMapper)
+	m.fieldConfigs = make(map[string]FieldConfig)
+	m.parsers = parsers
+	m.localizable = localizable
+	m.enums = make(map[reflect.Type]bool)
+	m.filters = make(map[reflect.Type][]ValueFilter)
+	m.formFieldTypes = make(map[reflect.Type]bool)
+	m.beforeField, m.afterField = nil, nil
+	m.beforeMap, m.afterMap = nil, nil
+	m.version++
+}
+
+// defaultConverters returns the built-in converters, and the types among them
+// whose plain numbers a locale may reformat; user-registered converters are
+// left as they are.
+func (m *Mapper) defaultConverters() (map[reflect.Type]ValueConverter, map[reflect.Type]bool) {
+	converters := map[reflect.Type]ValueConverter{
+		reflect.TypeOf(time.Duration(0)): func(v reflect.Value) string {
+			return formatDuration(v.Interface().(time.Duration), m.durationUnit)
+		},
+		reflect.TypeOf(time.Time{}): func(v reflect.Value) string {
+			t := v.Interface().(time.Time)
+			if t.IsZero() {
+				return ""
+			}
+			return t.Format(m.TimeLayout())
+		},
+		reflect.TypeOf(float64(0)): func(v reflect.Value) string {
+			return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+		},
+		reflect.TypeOf(float32(0)): func(v reflect.Value) string {
+			return strconv.FormatFloat(v.Float(), 'f', -1, 32)
+		},
+		reflect.TypeOf(int(0)): func(v reflect.Value) string {
+			return strconv.Itoa(int(v.Int()))
+		},
+		reflect.TypeOf(int64(0)): func(v reflect.Value) string {
+			return strconv.FormatInt(v.Int(), 10)
+		},
+		reflect.TypeOf(bool(false)): func(v reflect.Value) string {
+			return strconv.FormatBool(v.Bool())
+		},
+	}
+
+	// Integers stay plain, e.g. years or values for <input type="number">,
+	// unless a field opts in with FieldConfig.Group.
+	localizable := make(map[reflect.Type]bool)
+	for _, t := range []reflect.Type{reflect.TypeOf(float64(0)), reflect.TypeOf(float32(0))} {
+		localizable[t] = true
+	}
+	return converters, localizable
+}
+
+// Clone returns a Mapper with copies of m's registries and options, so a
+// request handler can register its own converters, parsers or field mappers
+// without touching the shared Mapper. Registrations on either side after the
+// clone don't affect the other.
+func (m *Mapper) Clone() *Mapper {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return &Mapper{
+		converters:            maps.Clone(m.converters),
+		conditionalConverters: cloneSlices(m.conditionalConverters),
+		fieldMappers:          maps.Clone(m.fieldMappers),
+		siblingMappers:        maps.Clone(m.siblingMappers),
+		fieldConfigs:          maps.Clone(m.fieldConfigs),
+		parsers:               maps.Clone(m.parsers),
+		localizable:           maps.Clone(m.localizable),
+		enums:                 maps.Clone(m.enums),
+		filters:               cloneSlices(m.filters),
+		formFieldTypes:        maps.Clone(m.formFieldTypes),
+		jsonNames:             m.jsonNames,
+		nameTransformer:       m.nameTransformer,
+		locale:                m.locale,
+		durationUnit:          m.durationUnit,
+		bytesEncoding:         m.bytesEncoding,
+		location:              m.location,
+		timeLayout:            m.timeLayout,
+		mergeSlices:           m.mergeSlices,
+		collectErrors:         m.collectErrors,
+		strictConversion:      m.strictConversion,
+		globalErrorsField:     m.globalErrorsField,
+		structErrorsField:     m.structErrorsField,
+		validateTag:           m.validateTag,
+		beforeField:           slices.Clip(m.beforeField),
+		afterField:            slices.Clip(m.afterField),
+		beforeMap:             slices.Clip(m.beforeMap),
+		afterMap:              slices.Clip(m.afterMap),
+	}
+}
+
+// cloneSlices copies a map of slices, clipping each slice so appends on one
+// copy never write into the other's backing array.
+func cloneSlices[K comparable, V any](src map[K][]V) map[K][]V {
+	dst := make(map[K][]V, len(src))
+	for k, v := range src {
+		dst[k] = slices.Clip(v)
+	}
+	return dst
+}
+
+// TimeLayout returns the layout used for time.Time values.
+func (m *Mapper) TimeLayout() string {
+	if m.timeLayout == "" {
+		return time.RFC3339
+	}
+	return m.timeLayout
+}
+
 func (m *Mapper) RegisterConverter(t reflect.Type, converter ValueConverter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.converters[t] = converter
+	delete(m.localizable, t)
+	delete(m.enums, t)
+}
+
+// UnregisterConverter removes the converter and conditional converters for t,
+// built-in ones included, so values of t fall back to the default handling
+// for their kind.
+func (m *Mapper) UnregisterConverter(t reflect.Type) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.converters, t)
+	delete(m.conditionalConverters, t)
+	delete(m.localizable, t)
+	delete(m.enums, t)
+}
+
+// RegisterConverterFor registers fn as the converter for T without going
+// through reflect in the caller.
+func RegisterConverterFor[T any](m *Mapper, fn func(T) string) {
+	m.RegisterConverter(reflect.TypeFor[T](), func(v reflect.Value) string {
+		return fn(v.Interface().(T))
+	})
+}
+
+// RegisterConditionalConverter registers a converter for t that only applies
+// when condition holds for the value and its parent document struct.
+// Conditional converters are tried in registration order before the plain
+// converter for t.
+func (m *Mapper) RegisterConditionalConverter(t reflect.Type, condition ConverterCondition, converter ValueConverter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.conditionalConverters[t] = append(m.conditionalConverters[t], conditionalConverter{
+		condition: condition,
+		converter: converter,
+	})
+}
+
+// SiblingEquals is a ConverterCondition that holds when the named sibling
+// field of the parent document struct converts to value.
+func SiblingEquals(field, value string) ConverterCondition {
+	return func(_ reflect.Value, docParent reflect.Value) bool {
+		if !docParent.IsValid() || docParent.Kind() != reflect.Struct {
+			return false
+		}
+		sibling := docParent.FieldByName(field)
+		return sibling.IsValid() && fmt.Sprint(sibling.Interface()) == value
+	}
 }
 
 func (m *Mapper) RegisterFieldMapper(fieldPath string, mapper FieldMapper) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.fieldMappers[fieldPath] = mapper
+	m.version++
+}
+
+// RegisterPathConverter formats the values at fieldPath with converter,
+// like MapOptions.FieldConverters but for every call. Paths accept patterns
+// such as "Items[*].Price". It replaces any field mapper registered for the
+// same path.
+func (m *Mapper) RegisterPathConverter(fieldPath string, converter ValueConverter) {
+	m.RegisterFieldMapper(fieldPath, converterMapper(converter))
+}
+
+// UnregisterFieldMapper removes the field mapper, sibling field mapper or
+// path converter registered for exactly fieldPath.
+func (m *Mapper) UnregisterFieldMapper(fieldPath string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.fieldMappers, fieldPath)
+	delete(m.siblingMappers, fieldPath)
+	m.version++
+}
+
+func (m *Mapper) RegisterSiblingFieldMapper(fieldPath string, mapper SiblingFieldMapper) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.siblingMappers[fieldPath] = mapper
+	m.version++
+}
+
+func (m *Mapper) fieldMappersFor(fieldPath pathKey) (FieldMapper, SiblingFieldMapper) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	fieldMapper, _ := lookupPathKey(m.fieldMappers, fieldPath)
+	siblingMapper, _ := lookupPathKey(m.siblingMappers, fieldPath)
+	return fieldMapper, siblingMapper
+}
+
+func (m *Mapper) convertersFor(t reflect.Type) ([]conditionalConverter, ValueConverter, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.conditionalConverters[t], m.converters[t], m.localizable[t]
 }
 
 func (m *Mapper) MapToForm(doc any, err error, formData any) error {
+	return m.mapToForm(doc, err, formData, &mapState{})
+}
+
+// Map allocates a TForm and maps doc into it. valErr may be nil.
+func Map[TDoc, TForm any](m *Mapper, doc *TDoc, valErr *ValidationError) (*TForm, error) {
+	form := new(TForm)
+	if err := m.MapToForm(doc, valErr, form); err != nil {
+		return nil, err
+	}
+	return form, nil
+}
+
+// mapState holds what is specific to a single mapping call, so per-call
+// options never leak into the Mapper's shared registries.
+type mapState struct {
+	valErr       *ValidationError
+	fieldMappers map[string]FieldMapper
+	skipFields   []string
+	onlyFields   []string
+	fieldAliases map[string]string
+	defaults     map[string]string
+	locale       *Locale
+	location     *time.Location
+	mergeSlices  bool
+	report       *MapReport
+
+	collectErrors bool
+	errs          []error
+	limits        mapLimits
+
+	// compiled holds the structs Plan.Execute resolved ahead of time, by
+	// path.
+	compiled map[string]*compiledStruct
+
+	// path backs the pathKeys of the call.
+	path []byte
+}
+
+func (m *Mapper) mapToForm(doc any, err error, formData any, st *mapState) error {
 	docVal := reflect.ValueOf(doc)
 	formVal := reflect.ValueOf(formData)
 
+	valErr, err := asValidationError(err)
+	if err != nil {
+		return err
+	}
+
+	// Maps are references already, so a map document needs no pointer.
+	if (docVal.Kind() != reflect.Ptr && docVal.Kind() != reflect.Map) || formVal.Kind() != reflect.Ptr {
+		return fmt.Errorf("%w: doc and formData must be pointers", ErrNotPointer)
+	}
+
+	if docVal.IsNil() || formVal.IsNil() {
+		return fmt.Errorf("%w: doc and formData cannot be nil", ErrNilInput)
+	}
+
+	if docVal.Kind() == reflect.Ptr {
+		docVal = docVal.Elem()
+	}
+	formVal = formVal.Elem()
+
+	if err := m.prepareState(st); err != nil {
+		return err
+	}
+	st.valErr = valErr
+
+	before, after := m.mapHooks()
+	if err := runMapHooks(before, doc, formData, valErr); err != nil {
+		return err
+	}
+
+	mapDoc := m.mapStruct
+	if docVal.Kind() == reflect.Map {
+		mapDoc = m.mapDocMap
+	}
+	if err := mapDoc(docVal, formVal, st, rootPath(&st.path)); err != nil {
+		return err
+	}
+
+	if err := m.mapAliases(docVal, formVal, st); err != nil {
+		return err
+	}
+
+	if err := m.mapGlobalErrors(formVal, valErr); err != nil {
+		return err
+	}
+
+	if err := runMapHooks(after, doc, formData, valErr); err != nil {
+		st.errs = append(st.errs, err)
+	}
+	return errors.Join(st.errs...)
+}
+
+// mapGlobalErrors fills the form field named by WithGlobalErrorsField.
+func (m *Mapper) mapGlobalErrors(formVal reflect.Value, valErr *ValidationError) error {
+	if m.globalErrorsField == "" {
+		return nil
+	}
+	if !setErrorsField(formVal, m.globalErrorsField, valErr, GlobalPath) {
+		return fmt.Errorf("%w: global errors field %s has unsupported type %s", ErrInvalidOption, m.globalErrorsField, formVal.FieldByName(m.globalErrorsField).Type())
+	}
+	return nil
+}
+
+// mapStructErrors fills the form field named by WithStructErrorsField with
+// the errors of the nested struct at structPath.
+func (m *Mapper) mapStructErrors(formVal reflect.Value, st *mapState, structPath pathKey) error {
+	if m.structErrorsField == "" || structPath.n == 0 {
+		return nil
+	}
+	if !setErrorsField(formVal, m.structErrorsField, st.valErr, structPath.String()) {
+		return fieldError(structPath.String(), ErrInvalidOption, fmt.Errorf("struct errors field %s has unsupported type %s", m.structErrorsField, formVal.FieldByName(m.structErrorsField).Type()))
+	}
+	return nil
+}
+
+// setErrorsField fills the field name of the form struct formVal with the
+// errors at path: every message for a []string, the first for a string, or
+// both for a leaf form type. Forms without the field are left alone; it
+// reports false for a field of another type.
+func setErrorsField(formVal reflect.Value, name string, valErr *ValidationError, path string) bool {
+	if formVal.Kind() != reflect.Struct {
+		return true
+	}
+	field := formVal.FieldByName(name)
+	if !field.IsValid() || !field.CanSet() {
+		return true
+	}
+
+	if ff, ok := asFormField(field); ok {
+		setFieldErrors(ff, valErr, path)
+		return true
+	}
+	switch {
+	case field.Type() == reflect.TypeOf([]string(nil)):
+		field.Set(reflect.ValueOf(valErr.MsgsFor(path)))
+	case field.Kind() == reflect.String:
+		field.SetString(valErr.MsgFor(path))
+	default:
+		return false
+	}
+	return true
+}
+
+// asValidationError accepts the error MapToForm takes: nil, or a possibly nil
+// *ValidationError.
+func asValidationError(err error) (*ValidationError, error) {
 	if err == nil {
 		err = &ValidationError{}
 	}
 
 	valErr, ok := err.(*ValidationError)
 	if !ok {
-		return fmt.Errorf("expected ValidationError, got %T", err)
+		return nil, fmt.Errorf("%w, got %T", ErrNotValidationError, err)
+	}
+
+	// A nil *ValidationError, as returned by Validate on success, arrives
+	// here as a non-nil error interface.
+	if valErr == nil {
+		valErr = &ValidationError{}
 	}
 
 	if valErr.Errors == nil {
 		valErr.Errors = make(Errors)
 	}
+	return valErr, nil
+}
 
-	if docVal.Kind() != reflect.Ptr || formVal.Kind() != reflect.Ptr {
-		return fmt.Errorf("doc and formData must be pointers")
+// prepareState fills in the mapper's options that st doesn't override.
+func (m *Mapper) prepareState(st *mapState) error {
+	if st.locale == nil && m.locale != "" {
+		loc, ok := LookupLocale(m.locale)
+		if !ok {
+			return fmt.Errorf("%w: unknown locale %q", ErrInvalidOption, m.locale)
+		}
+		st.locale = &loc
 	}
 
-	if docVal.IsNil() || formVal.IsNil() {
-		return fmt.Errorf("doc and formData cannot be nil")
+	if !validDurationUnit(m.durationUnit) {
+		return fmt.Errorf("%w: unknown duration unit %q", ErrInvalidOption, m.durationUnit)
 	}
 
-	docVal = docVal.Elem()
-	formVal = formVal.Elem()
+	if !validBytesEncoding(m.bytesEncoding) {
+		return fmt.Errorf("%w: unknown bytes encoding %q", ErrInvalidOption, m.bytesEncoding)
+	}
 
-	return m.mapStruct(docVal, formVal, valErr, "")
+	if st.location == nil {
+		st.location = m.location
+	}
+	st.mergeSlices = st.mergeSlices || m.mergeSlices
+	st.collectErrors = st.collectErrors || m.collectErrors
+	return nil
 }
 
-func (m *Mapper) mapStruct(docVal, formVal reflect.Value, valErr *ValidationError, pathPrefix string) error {
+// skipsPath reports whether the call's SkipFields or OnlyFields leave
+// fieldPath out, and why.
+func (st *mapState) skipsPath(fieldPath pathKey) (reason string, skip bool) {
+	if len(st.skipFields) == 0 && len(st.onlyFields) == 0 {
+		return "", false
+	}
+
+	path := fieldPath.String()
+	if matchAnyPath(st.skipFields, path) {
+		return "skipped by MapOptions.SkipFields", true
+	}
+	if !selectsPath(st.onlyFields, path) {
+		return "not selected by MapOptions.OnlyFields", true
+	}
+	return "", false
+}
+
+// fail records err and lets mapping continue when collecting errors;
+// otherwise, or when a limit was exceeded, it hands err back to stop mapping.
+func (st *mapState) fail(err error) error {
+	if !st.collectErrors || errors.Is(err, ErrLimitExceeded) {
+		return err
+	}
+	st.errs = append(st.errs, err)
+	return nil
+}
+
+func (m *Mapper) mapStruct(docVal, formVal reflect.Value, st *mapState, pathPrefix pathKey) error {
+	if err := st.limits.enter(pathPrefix); err != nil {
+		return err
+	}
+	defer st.limits.leave()
+
 	docType := docVal.Type()
 	formType := formVal.Type()
 
-	for i := 0; i < docType.NumField(); i++ {
-		docField := docType.Field(i)
-		docFieldVal := docVal.Field(i)
+	if cs := st.compiled[string(pathPrefix.bytes())]; cs != nil && cs.docType == docType && cs.formType == formType {
+		for _, f := range cs.fields {
+			if err := m.mapStructField(docVal, formVal, st, pathPrefix.field(f.name), f); err != nil {
+				return err
+			}
+		}
+		return m.mapStructErrors(formVal, st, pathPrefix)
+	}
 
-		if !docField.IsExported() {
+	for _, plan := range m.structPlan(docType, formType) {
+		fieldPath, formFieldName, fc, skip := m.resolvePath(plan.tag, pathPrefix)
+		if skip {
+			st.reportSkip(fieldPath, "skipped by formmap tag or config")
 			continue
 		}
-
-		fieldName := m.getFieldName(docField)
-		if fieldName == "-" {
+		if reason, skipped := st.skipsPath(fieldPath); skipped {
+			st.reportSkip(fieldPath, reason)
+			continue
+		}
+		if target, ok := st.fieldAliases[string(fieldPath.bytes())]; ok {
+			st.reportSkipf(fieldPath, "mapped to %s by MapOptions.FieldAliases", target)
 			continue
 		}
 
-		formField, found := m.findFormField(formType, fieldName)
+		formField, found := m.planFormField(plan, formType, formFieldName)
 		if !found {
+			st.reportSkipf(fieldPath, "no form field %s", formFieldName)
 			continue
 		}
 
-		formFieldVal := formVal.FieldByName(formField.Name)
-		if !formFieldVal.IsValid() || !formFieldVal.CanSet() {
-			continue
+		f := structField{index: plan.index, fc: fc, formField: formField}
+		if err := m.mapStructField(docVal, formVal, st, fieldPath, f); err != nil {
+			return err
 		}
+	}
+
+	return m.mapStructErrors(formVal, st, pathPrefix)
+}
+
+// structField is a document field resolved against its form field, per call
+// by mapStruct or once by Compile.
+type structField struct {
+	index     int
+	name      string
+	fc        FieldConfig
+	formField reflect.StructField
+	// mappers holds the field mappers Compile looked up; nil looks them up
+	// per call.
+	mappers *pathMappers
+}
+
+type pathMappers struct {
+	field   FieldMapper
+	sibling SiblingFieldMapper
+}
+
+func (m *Mapper) mapStructField(docVal, formVal reflect.Value, st *mapState, fieldPath pathKey, f structField) error {
+	docFieldVal := docVal.Field(f.index)
+	formFieldVal := formVal.FieldByIndex(f.formField.Index)
+	if !formFieldVal.CanSet() {
+		st.reportSkipf(fieldPath, "form field %s is not settable", f.formField.Name)
+		return nil
+	}
+
+	if err := st.limits.visit(); err != nil {
+		return fieldError(fieldPath.String(), ErrMappingFailed, err)
+	}
+
+	if isList(docFieldVal) {
+		m.mapSliceMeta(docVal.Type().Field(f.index), docFieldVal, formVal, f.formField.Name)
+	}
 
-		fieldPath := fieldName
-		if pathPrefix != "" {
-			fieldPath = pathPrefix + "." + fieldPath
+	err := m.withFieldHooks(fieldPath, docFieldVal, formFieldVal, func() error {
+		var handled bool
+		var err error
+		if f.mappers != nil {
+			handled, err = m.runFieldMapper(st, docVal, docFieldVal, formFieldVal, fieldPath, f.mappers.field, f.mappers.sibling)
+		} else {
+			handled, err = m.applyFieldMapper(st, docVal, docFieldVal, formFieldVal, fieldPath)
+		}
+		if handled {
+			return err
 		}
 
-		if mapper, ok := m.fieldMappers[fieldPath]; ok {
-			if err := mapper(docFieldVal, formFieldVal, fieldPath, valErr); err != nil {
-				return fmt.Errorf("custom mapper for field %s failed: %w", fieldPath, err)
-			}
-			continue
+		if err := m.mapField(docVal, docFieldVal, formFieldVal, st, fieldPath, f.fc, f.formField); err != nil {
+			return fieldError(fieldPath.String(), ErrMappingFailed, err)
 		}
+		return nil
+	})
+	if err != nil {
+		return st.fail(err)
+	}
+	return nil
+}
+
+func (m *Mapper) applyFieldMapper(st *mapState, docParent, docFieldVal, formFieldVal reflect.Value, path pathKey) (bool, error) {
+	fieldMapper, siblingMapper := m.fieldMappersFor(path)
+	return m.runFieldMapper(st, docParent, docFieldVal, formFieldVal, path, fieldMapper, siblingMapper)
+}
 
-		if err := m.mapField(docFieldVal, formFieldVal, valErr, fieldPath, formField); err != nil {
-			return fmt.Errorf("mapping field %s failed: %w", fieldPath, err)
+// runFieldMapper runs the call's field mapper for path, or else fieldMapper
+// or siblingMapper, the ones registered for it.
+func (m *Mapper) runFieldMapper(st *mapState, docParent, docFieldVal, formFieldVal reflect.Value, path pathKey, fieldMapper FieldMapper, siblingMapper SiblingFieldMapper) (bool, error) {
+	if mapper, ok := lookupPathKey(st.fieldMappers, path); ok {
+		fieldMapper = mapper
+	}
+
+	if fieldMapper != nil {
+		fieldPath := path.String()
+		if err := fieldMapper(docFieldVal, formFieldVal, fieldPath, st.valErr); err != nil {
+			return true, &FieldError{Path: fieldPath, Kind: ErrFieldMapperFailed, Err: err}
 		}
+		st.reportMapped(path, ConversionFieldMapper, formFieldVal)
+		return true, nil
 	}
 
-	return nil
+	if siblingMapper != nil {
+		fieldPath := path.String()
+		if err := siblingMapper(docParent, docFieldVal, formFieldVal, fieldPath, st.valErr); err != nil {
+			return true, &FieldError{Path: fieldPath, Kind: ErrFieldMapperFailed, Err: err}
+		}
+		st.reportMapped(path, ConversionFieldMapper, formFieldVal)
+		return true, nil
+	}
+
+	return false, nil
 }
 
 func (m *Mapper) getFieldName(field reflect.StructField) string {
+	if m.jsonNames {
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			name, _, _ := strings.Cut(tag, ",")
+			if name != "" {
+				return name
+			}
+		}
+	}
 	return field.Name
 }
 
-func (m *Mapper) findFormField(formType reflect.Type, fieldName string) (reflect.StructField, bool) {
-	return formType.FieldByName(fieldName)
+// resolveField returns the error path of a document field and the name of the
+// form field it maps to. The path always follows the document struct so it
+// lines up with validator error keys; the form field name can be changed with
+// a `formmap:"Name"` tag or a configured Name, and `formmap:"-"` or a
+// configured Skip drops the field.
+func (m *Mapper) resolveField(docField reflect.StructField, pathPrefix string) (fieldPath, formFieldName string, fc FieldConfig, skip bool) {
+	return m.resolveTag(m.parseFieldTag(docField), pathPrefix)
 }
 
-func (m *Mapper) mapField(docFieldVal, formFieldVal reflect.Value, valErr *ValidationError, fieldPath string, formField reflect.StructField) error {
+// parseFormmapTag splits a `formmap:"Name,layout=2006-01-02"` tag into the
+// form field name and per-field options. The name may be omitted. Because
+// layouts and separators can contain commas, layout= and sep= take the rest of
+// the tag and must come last.
+func parseFormmapTag(tag string) (name string, fc FieldConfig) {
+	for first := true; tag != ""; first = false {
+		var part string
+		if strings.HasPrefix(tag, "layout=") || strings.HasPrefix(tag, "sep=") {
+			part, tag = tag, ""
+		} else {
+			part, tag, _ = strings.Cut(tag, ",")
+		}
+
+		key, value, isOption := strings.Cut(part, "=")
+		if !isOption {
+			if first {
+				name = part
+			} else if strings.TrimSpace(part) == "group" {
+				fc.Group = true
+			}
+			continue
+		}
+
+		switch strings.TrimSpace(key) {
+		case "layout":
+			fc.TimeLayout = value
+		case "unit":
+			fc.DurationUnit = value
+		case "sep":
+			fc.Separator = value
+		}
+	}
+
+	return name, fc
+}
+
+func (m *Mapper) mapField(docParent, docFieldVal, formFieldVal reflect.Value, st *mapState, fieldPath pathKey, fc FieldConfig, formField reflect.StructField) error {
 	formFieldType := formField.Type
 
-	if formFieldType.Name() == "FormInputData" {
-		return m.mapFormInputData(docFieldVal, formFieldVal, valErr, fieldPath)
+	if m.isFormField(formFieldType) {
+		return m.mapFormField(docParent, docFieldVal, formFieldVal, st, fieldPath, fc)
+	}
+
+	// Interface fields dispatch on their dynamic value, and pointers are
+	// followed when the form side holds the value directly.
+	if docFieldVal.Kind() == reflect.Interface || (docFieldVal.Kind() == reflect.Ptr && formFieldVal.Kind() != reflect.Ptr) {
+		if docFieldVal.IsNil() {
+			return nil
+		}
+		return m.mapField(docParent, docFieldVal.Elem(), formFieldVal, st, fieldPath, fc, formField)
 	}
 
-	if docFieldVal.Kind() == reflect.Slice && formFieldVal.Kind() == reflect.Slice {
-		return m.mapSlice(docFieldVal, formFieldVal, valErr, fieldPath)
+	if isList(docFieldVal) && isList(formFieldVal) {
+		return m.mapSlice(docParent, docFieldVal, formFieldVal, st, fieldPath, fc)
+	}
+
+	if docFieldVal.Kind() == reflect.Map && formFieldVal.Kind() == reflect.Map {
+		return m.mapMap(docParent, docFieldVal, formFieldVal, st, fieldPath, fc)
 	}
 
 	if docFieldVal.Kind() == reflect.Struct && formFieldVal.Kind() == reflect.Struct {
-		return m.mapStruct(docFieldVal, formFieldVal, valErr, fieldPath)
+		return m.mapStruct(docFieldVal, formFieldVal, st, fieldPath)
+	}
+
+	if docFieldVal.Kind() == reflect.Map && formFieldVal.Kind() == reflect.Struct {
+		return m.mapDocMap(docFieldVal, formFieldVal, st, fieldPath)
 	}
 
 	if docFieldVal.Kind() == reflect.Ptr && formFieldVal.Kind() == reflect.Ptr {
@@ -184,122 +992,521 @@ func (m *Mapper) mapField(docFieldVal, formFieldVal reflect.Value, valErr *Valid
 			formFieldVal.Set(reflect.New(formFieldVal.Type().Elem()))
 		}
 
-		return m.mapField(docFieldVal.Elem(), formFieldVal.Elem(), valErr, fieldPath, formField)
+		return m.mapField(docParent, docFieldVal.Elem(), formFieldVal.Elem(), st, fieldPath, fc, formField)
 	}
 
 	return nil
 }
 
-func (m *Mapper) mapFormInputData(docFieldVal, formFieldVal reflect.Value, valErr *ValidationError, fieldPath string) error {
-	value := m.convertValue(docFieldVal)
+func (m *Mapper) mapFormField(docParent, docFieldVal, formFieldVal reflect.Value, st *mapState, fieldPath pathKey, fc FieldConfig) error {
+	docFieldVal = st.inLocation(dynamicValue(docFieldVal))
 
-	error := valErr.MsgFor(fieldPath)
+	value, ok, err := fc.convert(docFieldVal)
+	if err != nil {
+		return err
+	}
+	if ok && fc.Precision != nil {
+		value = st.formatNumber(value)
+	}
+	if !ok && m.joinsList(docFieldVal) {
+		if err := st.limits.checkLen(reflect.Indirect(docFieldVal).Len()); err != nil {
+			return err
+		}
+		value, ok = m.joinList(st, docFieldVal, docParent, fc.separator()), true
+	}
+	if !ok {
+		var err error
+		if value, err = m.convertFieldValue(st, docFieldVal, docParent); err != nil {
+			return err
+		}
+		if fc.Group && isInteger(docFieldVal) {
+			value = st.formatNumber(value)
+		}
+	}
+	value = m.filterValue(docFieldVal, value)
+
+	if value == "" {
+		if def, ok := lookupPathKey(st.defaults, fieldPath); ok {
+			value = def
+		} else {
+			value = fc.Default
+		}
+	}
 
-	valueField := formFieldVal.FieldByName("Value")
-	errorField := formFieldVal.FieldByName("Error")
+	ff, ok := asFormField(formFieldVal)
+	if !ok {
+		return fmt.Errorf("form field %s is not addressable", fieldPath)
+	}
+	ff.SetValue(value)
+	var msg string
+	msgs := st.valErr.msgsAt(fieldPath)
+	if len(msgs) > 0 {
+		msg = msgs[0]
+	}
+	ff.SetError(msg)
+	if ef, ok := ff.(ErrorsField); ok {
+		ef.SetErrors(msgs)
+	}
 
-	if valueField.IsValid() && valueField.CanSet() {
-		valueField.SetString(value)
+	if formFieldVal.Kind() == reflect.Struct {
+		setStringField(formFieldVal, "Label", fc.Label)
+		setStringField(formFieldVal, "Placeholder", fc.Placeholder)
 	}
 
-	if errorField.IsValid() && errorField.CanSet() {
-		errorField.SetString(error)
+	if st.report != nil {
+		st.reportMapped(fieldPath, m.valueConversion(docFieldVal, fc), formFieldVal)
 	}
 
 	return nil
 }
 
-func (m *Mapper) mapSlice(docSlice, formSlice reflect.Value, valErr *ValidationError, fieldPath string) error {
-	if formSlice.Len() != docSlice.Len() {
-		newSlice := reflect.MakeSlice(formSlice.Type(), docSlice.Len(), docSlice.Len())
+func setStringField(v reflect.Value, name, value string) {
+	if value == "" {
+		return
+	}
+	if f := v.FieldByName(name); f.IsValid() && f.CanSet() && f.Kind() == reflect.String {
+		f.SetString(value)
+	}
+}
 
-		elemType := formSlice.Type().Elem()
-		if elemType.Kind() == reflect.Struct {
-			for i := 0; i < newSlice.Len(); i++ {
-				newSlice.Index(i).Set(reflect.New(elemType).Elem())
+func (m *Mapper) mapSlice(docParent, docSlice, formSlice reflect.Value, st *mapState, fieldPath pathKey, fc FieldConfig) error {
+	if err := st.limits.checkLen(docSlice.Len()); err != nil {
+		return err
+	}
+	if err := st.limits.enter(fieldPath); err != nil {
+		return err
+	}
+	defer st.limits.leave()
+
+	n := docSlice.Len()
+	if formSlice.Kind() == reflect.Array {
+		n = min(n, formSlice.Len())
+	} else if prevLen := formSlice.Len(); prevLen != n {
+		// Elements outside a partial mapping must survive too.
+		keep := st.mergeSlices || len(st.onlyFields) > 0
+		if formSlice.Cap() >= n {
+			formSlice.SetLen(n)
+		} else {
+			resized := reflect.MakeSlice(formSlice.Type(), n, n)
+			if keep {
+				reflect.Copy(resized, formSlice)
 			}
+			formSlice.Set(resized)
 		}
 
-		formSlice.Set(newSlice)
+		// Whether a resized slice reuses elements shouldn't depend on its
+		// capacity.
+		from := 0
+		if keep {
+			from = prevLen
+		}
+		for i := from; i < n; i++ {
+			formSlice.Index(i).SetZero()
+		}
 	}
 
-	for i := 0; i < docSlice.Len(); i++ {
+	for i := 0; i < n; i++ {
 		docElem := docSlice.Index(i)
 		formElem := formSlice.Index(i)
 
-		indexedPath := fmt.Sprintf("%s[%d]", fieldPath, i)
+		indexedPath := fieldPath.index(i)
+		if reason, skipped := st.skipsPath(indexedPath); skipped {
+			st.reportSkip(indexedPath, reason)
+			continue
+		}
+		if err := st.limits.visit(); err != nil {
+			return fieldError(indexedPath.String(), ErrMappingFailed, err)
+		}
+
+		if err := m.mapElem(docParent, docElem, formElem, st, indexedPath, fc); err != nil {
+			if err := st.fail(err); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// isInteger reports whether v holds an integer, through pointers and SQL
+// null wrappers.
+func isInteger(v reflect.Value) bool {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return false
+		}
+		v = v.Elem()
+	}
+	if inner, ok := sqlNullValue(v); ok {
+		v = inner
+	}
+	return v.CanInt() || v.CanUint()
+}
+
+// isList reports whether v is a slice or a fixed-size array; both map
+// element by element with indexed paths.
+func isList(v reflect.Value) bool {
+	return v.Kind() == reflect.Slice || v.Kind() == reflect.Array
+}
+
+func (m *Mapper) mapMap(docParent, docMap, formMap reflect.Value, st *mapState, fieldPath pathKey, fc FieldConfig) error {
+	if docMap.IsNil() {
+		formMap.SetZero()
+		return nil
+	}
+	if err := st.limits.checkLen(docMap.Len()); err != nil {
+		return err
+	}
+	if err := st.limits.enter(fieldPath); err != nil {
+		return err
+	}
+	defer st.limits.leave()
+
+	formType := formMap.Type()
+	newMap := reflect.MakeMapWithSize(formType, docMap.Len())
+
+	// Entries outside a partial mapping must survive the rebuild.
+	if len(st.onlyFields) > 0 && !formMap.IsNil() {
+		iter := formMap.MapRange()
+		for iter.Next() {
+			newMap.SetMapIndex(iter.Key(), iter.Value())
+		}
+	}
+
+	iter := docMap.MapRange()
+	for iter.Next() {
+		keyPath := fieldPath.key(iter.Key())
+		if reason, skipped := st.skipsPath(keyPath); skipped {
+			st.reportSkip(keyPath, reason)
+			continue
+		}
+		if err := st.limits.visit(); err != nil {
+			return fieldError(keyPath.String(), ErrMappingFailed, err)
+		}
 
-		if docElem.Kind() == reflect.Struct && formElem.Kind() == reflect.Struct {
-			if err := m.mapStruct(docElem, formElem, valErr, indexedPath); err != nil {
+		key, err := mapKey(iter.Key(), formType.Key())
+		if err != nil {
+			if err := st.fail(fieldError(keyPath.String(), ErrMappingFailed, err)); err != nil {
 				return err
 			}
-		} else if formElem.Type().Name() == "FormInputData" {
-			if err := m.mapFormInputData(docElem, formElem, valErr, indexedPath); err != nil {
+			continue
+		}
+
+		formElem := reflect.New(formType.Elem()).Elem()
+		if err := m.mapElem(docParent, iter.Value(), formElem, st, keyPath, fc); err != nil {
+			if err := st.fail(err); err != nil {
 				return err
 			}
+			continue
 		}
+
+		newMap.SetMapIndex(key, formElem)
+	}
+
+	formMap.Set(newMap)
+	return nil
+}
+
+func mapKey(key reflect.Value, formKeyType reflect.Type) (reflect.Value, error) {
+	if key.Type() == formKeyType {
+		return key, nil
+	}
+
+	if formKeyType.Kind() == reflect.String {
+		return reflect.ValueOf(fmt.Sprint(key.Interface())).Convert(formKeyType), nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("%w: cannot use map key of type %s as %s", ErrUnsupportedType, key.Type(), formKeyType)
+}
+
+// mapElem maps one slice, array or map element. Elements inherit the field's
+// options unless configuration targets the element path itself.
+func (m *Mapper) mapElem(docParent, docElem, formElem reflect.Value, st *mapState, elemPath pathKey, fc FieldConfig) error {
+	return m.withFieldHooks(elemPath, docElem, formElem, func() error {
+		return m.mapElemValue(docParent, docElem, formElem, st, elemPath, fc)
+	})
+}
+
+func (m *Mapper) mapElemValue(docParent, docElem, formElem reflect.Value, st *mapState, elemPath pathKey, fc FieldConfig) error {
+	fc = m.elemConfig(fc, elemPath)
+
+	if handled, err := m.applyFieldMapper(st, docParent, docElem, formElem, elemPath); handled {
+		return err
+	}
+
+	for docElem.Kind() == reflect.Interface || docElem.Kind() == reflect.Ptr {
+		if m.isFormField(formElem.Type()) {
+			break
+		}
+		if docElem.IsNil() {
+			return nil
+		}
+		docElem = docElem.Elem()
+	}
+
+	if docElem.Kind() == reflect.Struct && formElem.Kind() == reflect.Struct && !m.isFormField(formElem.Type()) {
+		return m.mapStruct(docElem, formElem, st, elemPath)
+	}
+
+	if docElem.Kind() == reflect.Map && formElem.Kind() == reflect.Struct && !m.isFormField(formElem.Type()) {
+		return m.mapDocMap(docElem, formElem, st, elemPath)
+	}
+
+	if m.isFormField(formElem.Type()) {
+		return m.mapFormField(docParent, docElem, formElem, st, elemPath, fc)
 	}
 
 	return nil
 }
 
 func (m *Mapper) convertValue(v reflect.Value) string {
+	s, _ := m.convertFieldValue(nil, v, reflect.Value{})
+	return s
+}
+
+// convertFieldValue only fails in strict mode, for values that would
+// otherwise be stringified with fmt.Sprint.
+func (m *Mapper) convertFieldValue(st *mapState, v reflect.Value, docParent reflect.Value) (string, error) {
+	v = dynamicValue(v)
 	if !v.IsValid() {
-		return ""
+		return "", nil
 	}
 
 	if v.Kind() == reflect.Ptr {
 		if v.IsNil() {
-			return ""
+			return "", nil
 		}
 		v = v.Elem()
 	}
 
-	if v.Kind() != reflect.Bool && v.IsZero() {
-		return ""
+	v = st.inLocation(v)
+
+	if v.Kind() != reflect.Bool && isZero(v) && !m.isEnum(v.Type()) {
+		return "", nil
 	}
 
-	if converter, ok := m.converters[v.Type()]; ok {
-		return converter(v)
+	conditional, converter, localizable := m.convertersFor(v.Type())
+
+	for _, cc := range conditional {
+		if cc.condition(v, docParent) {
+			return cc.converter(v), nil
+		}
+	}
+
+	if converter != nil {
+		if localizable {
+			return st.formatNumber(converter(v)), nil
+		}
+		return converter(v), nil
+	}
+
+	if inner, ok := sqlNullValue(v); ok {
+		return m.convertFieldValue(st, inner, docParent)
+	}
+
+	if s, ok := marshalText(v); ok {
+		return s, nil
 	}
 
 	switch v.Kind() {
 	case reflect.String:
-		return v.String()
+		return v.String(), nil
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return strconv.FormatInt(v.Int(), 10)
+		return strconv.FormatInt(v.Int(), 10), nil
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return strconv.FormatUint(v.Uint(), 10)
+		return strconv.FormatUint(v.Uint(), 10), nil
 	case reflect.Float32, reflect.Float64:
-		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+		return st.formatFloat(v.Float(), 64), nil
 	case reflect.Bool:
-		return strconv.FormatBool(v.Bool())
-	case reflect.Interface:
-		if !v.IsNil() {
-			return m.convertValue(v.Elem())
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Complex64, reflect.Complex128:
+		return fmt.Sprint(v.Interface()), nil
+	case reflect.Slice:
+		if isBytes(v.Type()) {
+			return m.encodeBytes(v.Bytes()), nil
+		}
+	}
+
+	if m.strictConversion {
+		return "", fmt.Errorf("%w: no conversion for %s", ErrUnsupportedType, v.Type())
+	}
+	return fmt.Sprint(v.Interface()), nil
+}
+
+// dynamicValue unwraps interfaces so converters, filters and field options see
+// the concrete type stored in an `any` field. Nil interfaces are returned as
+// they are.
+func dynamicValue(v reflect.Value) reflect.Value {
+	for v.IsValid() && v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
+
+// marshalText formats v through encoding.TextMarshaler or fmt.Stringer, in
+// that order, so custom IDs and enums render without a registered converter.
+func marshalText(v reflect.Value) (string, bool) {
+	if !v.CanInterface() {
+		return "", false
+	}
+
+	// Checking the method sets first keeps plain values from being boxed.
+	t := v.Type()
+	if !t.Implements(textMarshalerType) && !t.Implements(stringerType) &&
+		(!v.CanAddr() || (!reflect.PointerTo(t).Implements(textMarshalerType) && !reflect.PointerTo(t).Implements(stringerType))) {
+		return "", false
+	}
+
+	candidates := []any{v.Interface()}
+	if v.CanAddr() {
+		candidates = append(candidates, v.Addr().Interface())
+	}
+
+	for _, c := range candidates {
+		if tm, ok := c.(encoding.TextMarshaler); ok {
+			if text, err := tm.MarshalText(); err == nil {
+				return string(text), true
+			}
+		}
+	}
+
+	for _, c := range candidates {
+		if s, ok := c.(fmt.Stringer); ok {
+			return s.String(), true
 		}
-		return ""
-	default:
-		return fmt.Sprint(v.Interface())
 	}
+
+	return "", false
+}
+
+type IsZeroer interface {
+	IsZero() bool
+}
+
+var isZeroerType = reflect.TypeOf((*IsZeroer)(nil)).Elem()
+
+// isZero prefers a type's own IsZero method over reflect's field-by-field
+// check, which misreports wrapper structs that carry internal state.
+func isZero(v reflect.Value) bool {
+	if v.CanInterface() && (v.Kind() == reflect.Interface || v.Type().Implements(isZeroerType)) {
+		if z, ok := v.Interface().(IsZeroer); ok {
+			return z.IsZero()
+		}
+	}
+	return v.IsZero()
+}
+
+// inLocation converts a time.Time, or a pointer to one, to the display time
+// zone.
+func (st *mapState) inLocation(v reflect.Value) reflect.Value {
+	if st == nil || st.location == nil || !v.IsValid() {
+		return v
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() || v.Type().Elem() != timeType {
+			return v
+		}
+		v = v.Elem()
+	}
+	if v.Type() != timeType {
+		return v
+	}
+
+	t := v.Interface().(time.Time)
+	if t.IsZero() {
+		return v
+	}
+	return reflect.ValueOf(t.In(st.location))
 }
 
 type MapOptions struct {
 	FieldConverters map[string]ValueConverter
-	SkipFields      []string
+	// SkipFields lists field paths to leave untouched. Entries may be
+	// patterns such as "Items[*].Price", "Metadata.*" or "**.Secret".
+	SkipFields []string
+	// OnlyFields limits mapping to these paths and everything below them,
+	// leaving the rest of the form untouched, e.g. to refresh just the inputs
+	// an HTMX request swaps. Patterns are accepted as in SkipFields.
+	OnlyFields []string
+	// FieldAliases maps document paths to form field paths for forms that
+	// flatten or rename fields, e.g. "Metadata.Author" to "AuthorName".
+	// Form paths use Go field names from the form root.
+	FieldAliases map[string]string
+	// Defaults pre-fills empty values by path, e.g. for "create" forms built
+	// from a zero document. They override `default` tags and config.
+	Defaults map[string]string
+	// Locale overrides the Mapper's locale for this call, e.g. "de-DE".
+	Locale string
+	// TimeZone overrides the Mapper's display time zone for this call.
+	TimeZone *time.Location
+	// MergeSlices preserves existing form slice elements, as WithSliceMerge.
+	MergeSlices bool
+	// CollectErrors reports every failing field, as WithCollectErrors.
+	CollectErrors bool
+
+	// MaxFields, MaxSliceLen and MaxDepth abort mapping with
+	// ErrLimitExceeded when a document, e.g. decoded from user-supplied JSON,
+	// has more fields and elements in total, a longer slice, array or map,
+	// or deeper nesting of structs, maps and lists (the root being 1) than
+	// allowed. Zero means no limit.
+	MaxFields   int
+	MaxSliceLen int
+	MaxDepth    int
 }
 
+// MapToFormWithOptions maps like MapToForm with opts applied to this call
+// only; the Mapper's registries are left untouched.
 func (m *Mapper) MapToFormWithOptions(doc any, err error, formData any, opts MapOptions) error {
-	for fieldPath, converter := range opts.FieldConverters {
-		m.RegisterFieldMapper(fieldPath, func(docField reflect.Value, formField reflect.Value, path string, err *ValidationError) error {
-			value := converter(docField)
-			errorMsg := err.MsgFor(path)
+	st, stErr := newOptionsState(opts)
+	if stErr != nil {
+		return stErr
+	}
+	return m.mapToForm(doc, err, formData, st)
+}
 
-			formField.FieldByName("Value").SetString(value)
-			formField.FieldByName("Error").SetString(errorMsg)
-			return nil
-		})
+func newOptionsState(opts MapOptions) (*mapState, error) {
+	st := &mapState{
+		fieldMappers: make(map[string]FieldMapper, len(opts.FieldConverters)),
+		skipFields:   opts.SkipFields,
+		onlyFields:   opts.OnlyFields,
+		fieldAliases: opts.FieldAliases,
+		defaults:     opts.Defaults,
+		location:     opts.TimeZone,
+		mergeSlices:  opts.MergeSlices,
+
+		collectErrors: opts.CollectErrors,
+		limits:        mapLimits{maxFields: opts.MaxFields, maxLen: opts.MaxSliceLen, maxDepth: opts.MaxDepth},
 	}
 
-	return m.MapToForm(doc, err, formData)
+	if opts.MaxFields < 0 || opts.MaxSliceLen < 0 || opts.MaxDepth < 0 {
+		return nil, fmt.Errorf("%w: negative limit", ErrInvalidOption)
+	}
+
+	if opts.Locale != "" {
+		loc, ok := LookupLocale(opts.Locale)
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown locale %q", ErrInvalidOption, opts.Locale)
+		}
+		st.locale = &loc
+	}
+
+	for fieldPath, converter := range opts.FieldConverters {
+		st.fieldMappers[fieldPath] = converterMapper(converter)
+	}
+
+	return st, nil
+}
+
+// converterMapper wraps a path-scoped converter as a FieldMapper that sets
+// the converted value and the path's error message.
+func converterMapper(converter ValueConverter) FieldMapper {
+	return func(docField reflect.Value, formField reflect.Value, path string, err *ValidationError) error {
+		ff, ok := asFormField(formField)
+		if !ok {
+			return fmt.Errorf("form field %s is not a FormField", path)
+		}
+		ff.SetValue(converter(docField))
+		setFieldErrors(ff, err, path)
+		return nil
+	}
 }
@@ -1,9 +1,13 @@
 package formmap
 
 import (
+	"encoding/hex"
 	"errors"
+	"net/netip"
 	"reflect"
 	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -96,6 +100,25 @@ func TestMain_MapToForm_InvalidError(t *testing.T) {
 	})
 }
 
+func TestMapper_MapToForm_NilValidationError(t *testing.T) {
+	mapper := NewMapper()
+
+	var valErr *ValidationError
+	formData := &TestFormData{}
+
+	if err := mapper.MapToForm(&TestDocument{Name: "Widget"}, valErr, formData); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	if formData.Name.Value != "Widget" || formData.Name.Error != "" {
+		t.Errorf("Name = %+v, want value without error", formData.Name)
+	}
+
+	if valErr.HasError("Name") || valErr.MsgFor("Name") != "" || !valErr.IsEmpty() {
+		t.Error("nil *ValidationError should behave as empty")
+	}
+}
+
 func TestMapper_MapToForm_Basic(t *testing.T) {
 	mapper := NewMapper()
 
@@ -195,6 +218,29 @@ func TestMapper_MapToForm_Basic(t *testing.T) {
 	}
 }
 
+func TestMap(t *testing.T) {
+	mapper := NewMapper()
+
+	doc := &TestDocument{Name: "Ada", Price: 9.5}
+	valErr := &ValidationError{Errors: Errors{"Name": ValidationField{Tag: "required"}}}
+
+	form, err := Map[TestDocument, TestFormData](mapper, doc, valErr)
+	if err != nil {
+		t.Fatalf("Map() error = %v", err)
+	}
+	if form.Name.Value != "Ada" || form.Name.Error != "This field is required" || form.Price.Value != "9.5" {
+		t.Errorf("form = %+v", form)
+	}
+
+	if _, err := Map[TestDocument, TestFormData](mapper, doc, nil); err != nil {
+		t.Errorf("Map() with nil valErr error = %v", err)
+	}
+
+	if _, err := Map[TestDocument, TestFormData](mapper, nil, nil); !errors.Is(err, ErrNilInput) {
+		t.Errorf("Map() with nil doc error = %v, want ErrNilInput", err)
+	}
+}
+
 func TestMapper_MapToForm_NestedStruct(t *testing.T) {
 	mapper := NewMapper()
 
@@ -231,6 +277,53 @@ func TestMapper_MapToForm_NestedStruct(t *testing.T) {
 	}
 }
 
+func TestMapper_AnonymousStructs(t *testing.T) {
+	mapper := NewMapper()
+
+	doc := &struct {
+		Name    string
+		Address *struct {
+			City string
+		}
+		Tags []struct{ Label string }
+	}{
+		Name:    "Ada",
+		Address: &struct{ City string }{City: "Cairo"},
+		Tags:    []struct{ Label string }{{Label: "admin"}},
+	}
+	form := &struct {
+		Name    FormInputData
+		Address struct{ City FormInputData }
+		Tags    []struct{ Label FormInputData }
+	}{}
+
+	valErr := &ValidationError{Errors: Errors{
+		"Address.City": ValidationField{Tag: "required"},
+	}}
+	if err := mapper.MapToForm(doc, valErr, form); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	if form.Name.Value != "Ada" || form.Address.City.Value != "Cairo" || form.Tags[0].Label.Value != "admin" {
+		t.Errorf("form = %+v", form)
+	}
+	if form.Address.City.Error != "This field is required" {
+		t.Errorf("Address.City error = %q", form.Address.City.Error)
+	}
+
+	back := &struct {
+		Name    string
+		Address *struct{ City string }
+		Tags    []struct{ Label string }
+	}{}
+	if err := mapper.MapFromForm(form, back); err != nil {
+		t.Fatalf("MapFromForm() error = %v", err)
+	}
+	if back.Name != "Ada" || back.Address == nil || back.Address.City != "Cairo" || back.Tags[0].Label != "admin" {
+		t.Errorf("round trip = %+v", back)
+	}
+}
+
 func TestMapper_MapToForm_Slices(t *testing.T) {
 	mapper := NewMapper()
 
@@ -366,6 +459,45 @@ func TestMapper_RegisterConverter(t *testing.T) {
 	}
 }
 
+func TestMapper_Clone(t *testing.T) {
+	type doc struct {
+		Price float64
+		Name  string
+	}
+	type form struct {
+		Price FormInputData
+		Name  FormInputData
+	}
+
+	shared := NewMapper(WithLocale("de-DE"))
+	shared.RegisterFilter(reflect.TypeOf(""), TrimSpace)
+
+	clone := shared.Clone()
+	clone.RegisterConverter(reflect.TypeOf(float64(0)), func(v reflect.Value) string {
+		return "€" + strconv.FormatFloat(v.Float(), 'f', 2, 64)
+	})
+	clone.RegisterFilter(reflect.TypeOf(""), Truncate(2))
+	shared.RegisterFilter(reflect.TypeOf(""), HTMLEscape)
+
+	d := &doc{Price: 1234.5, Name: " <Ada> "}
+
+	f := &form{}
+	if err := clone.MapToForm(d, nil, f); err != nil {
+		t.Fatalf("clone MapToForm() error = %v", err)
+	}
+	if f.Price.Value != "€1234.50" || f.Name.Value != "<A" {
+		t.Errorf("clone form = %+v", f)
+	}
+
+	f = &form{}
+	if err := shared.MapToForm(d, nil, f); err != nil {
+		t.Fatalf("shared MapToForm() error = %v", err)
+	}
+	if f.Price.Value != "1.234,5" || f.Name.Value != "&lt;Ada&gt;" {
+		t.Errorf("shared form = %+v", f)
+	}
+}
+
 func TestMapper_RegisterFieldMapper(t *testing.T) {
 	mapper := NewMapper()
 
@@ -437,6 +569,19 @@ func TestMapper_MapToFormWithOptions(t *testing.T) {
 	if formData.Items[1].Price.Value != "20" {
 		t.Errorf("Items[1].Price value = %v, want '20'", formData.Items[1].Price.Value)
 	}
+
+	if len(mapper.fieldMappers) != 0 {
+		t.Errorf("MapToFormWithOptions() registered %d field mappers on the Mapper", len(mapper.fieldMappers))
+	}
+
+	plain := &TestFormData{}
+	if err := mapper.MapToForm(doc, nil, plain); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	if plain.Price.Value != "150" {
+		t.Errorf("Price value after options call = %v, want '150'", plain.Price.Value)
+	}
 }
 
 func TestMapper_ErrorHandling(t *testing.T) {
@@ -574,3 +719,1631 @@ func TestMapper_CustomTypeConversion(t *testing.T) {
 		t.Errorf("Time conversion = %v, want %v", formData.CreatedAt.Value, expectedTime)
 	}
 }
+
+func TestMapper_RegisterConditionalConverter(t *testing.T) {
+	mapper := NewMapper()
+
+	mapper.RegisterConditionalConverter(reflect.TypeOf(time.Duration(0)),
+		func(v reflect.Value, _ reflect.Value) bool {
+			return v.Interface().(time.Duration) > 120*time.Minute
+		},
+		func(v reflect.Value) string {
+			return strconv.FormatFloat(v.Interface().(time.Duration).Hours(), 'f', -1, 64) + "h"
+		},
+	)
+
+	tests := []struct {
+		name     string
+		duration time.Duration
+		want     string
+	}{
+		{"condition holds", 150 * time.Minute, "2.5h"},
+		{"falls back to registry", 90 * time.Minute, "90"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formData := &TestFormData{}
+			if err := mapper.MapToForm(&TestDocument{Duration: tt.duration}, nil, formData); err != nil {
+				t.Fatalf("MapToForm() error = %v", err)
+			}
+			if formData.Duration.Value != tt.want {
+				t.Errorf("Duration value = %v, want %v", formData.Duration.Value, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapper_RegisterConditionalConverter_Sibling(t *testing.T) {
+	type priced struct {
+		Price    float64
+		Currency string
+	}
+	type pricedForm struct {
+		Price    FormInputData
+		Currency FormInputData
+	}
+
+	mapper := NewMapper()
+	mapper.RegisterConditionalConverter(reflect.TypeOf(float64(0)), SiblingEquals("Currency", "JPY"), func(v reflect.Value) string {
+		return strconv.FormatFloat(v.Float(), 'f', 0, 64)
+	})
+
+	tests := []struct {
+		currency string
+		want     string
+	}{
+		{"JPY", "1500"},
+		{"USD", "1500.4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.currency, func(t *testing.T) {
+			form := &pricedForm{}
+			if err := mapper.MapToForm(&priced{Price: 1500.4, Currency: tt.currency}, nil, form); err != nil {
+				t.Fatalf("MapToForm() error = %v", err)
+			}
+			if form.Price.Value != tt.want {
+				t.Errorf("Price value = %v, want %v", form.Price.Value, tt.want)
+			}
+		})
+	}
+}
+
+type testAmount struct {
+	units int
+	set   bool
+}
+
+func (a testAmount) IsZero() bool {
+	return !a.set
+}
+
+func (a testAmount) String() string {
+	return strconv.Itoa(a.units)
+}
+
+func TestMapper_WithStrictConversion(t *testing.T) {
+	type point struct{ X, Y int }
+	type doc struct {
+		Name  string
+		Where point
+		Attrs map[string]int
+		Addr  netip.Addr
+	}
+	type form struct {
+		Name  FormInputData
+		Where FormInputData
+		Attrs FormInputData
+		Addr  FormInputData
+	}
+
+	d := &doc{Name: "Ada", Where: point{1, 2}, Attrs: map[string]int{"a": 1}, Addr: netip.MustParseAddr("10.0.0.1")}
+
+	f := &form{}
+	if err := NewMapper().MapToForm(d, nil, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+	if f.Where.Value != "{1 2}" {
+		t.Errorf("lenient Where = %q, want fmt.Sprint output", f.Where.Value)
+	}
+
+	f = &form{}
+	err := NewMapper(WithStrictConversion(), WithCollectErrors()).MapToForm(d, nil, f)
+	if !errors.Is(err, ErrUnsupportedType) {
+		t.Fatalf("strict error = %v, want ErrUnsupportedType", err)
+	}
+
+	var paths []string
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var fe *FieldError
+		if errors.As(e, &fe) {
+			paths = append(paths, fe.Path)
+		}
+	}
+	if !reflect.DeepEqual(paths, []string{"Where", "Attrs"}) {
+		t.Errorf("failing paths = %v, want [Where Attrs]", paths)
+	}
+	if f.Name.Value != "Ada" || f.Addr.Value != "10.0.0.1" {
+		t.Errorf("convertible fields = %+v", f)
+	}
+}
+
+func TestMapper_DynamicAnyFields(t *testing.T) {
+	type money struct{ Cents int }
+	type doc struct {
+		Created any
+		Due     any `formmap:"layout=02/01/2006"`
+		Price   any
+		Title   any
+		Empty   any
+	}
+	type form struct {
+		Created FormInputData
+		Due     FormInputData
+		Price   FormInputData
+		Title   FormInputData
+		Empty   FormInputData
+	}
+
+	mapper := NewMapper(WithTimeLayout("2006-01-02"))
+	RegisterConverterFor(mapper, func(m money) string { return "$" + strconv.Itoa(m.Cents/100) + "." + strconv.Itoa(m.Cents%100) })
+	mapper.RegisterFilter(reflect.TypeOf(""), TrimSpace)
+
+	when := time.Date(2024, 3, 9, 15, 0, 0, 0, time.UTC)
+	d := &doc{Created: when, Due: &when, Price: money{Cents: 1999}, Title: "  Go  "}
+	f := &form{}
+	if err := mapper.MapToForm(d, nil, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	want := form{
+		Created: FormInputData{Value: "2024-03-09"},
+		Due:     FormInputData{Value: "09/03/2024"},
+		Price:   FormInputData{Value: "$19.99"},
+		Title:   FormInputData{Value: "Go"},
+	}
+	if *f != want {
+		t.Errorf("form = %+v, want %+v", *f, want)
+	}
+}
+
+func TestMapper_IsZeroer(t *testing.T) {
+	type doc struct {
+		Amount testAmount
+	}
+	type form struct {
+		Amount FormInputData
+	}
+
+	mapper := NewMapper()
+
+	tests := []struct {
+		name   string
+		amount testAmount
+		want   string
+	}{
+		{"explicit zero is not empty", testAmount{units: 0, set: true}, "0"},
+		{"unset is empty despite state", testAmount{units: 5, set: false}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &form{}
+			if err := mapper.MapToForm(&doc{Amount: tt.amount}, nil, f); err != nil {
+				t.Fatalf("MapToForm() error = %v", err)
+			}
+			if f.Amount.Value != tt.want {
+				t.Errorf("Amount value = %q, want %q", f.Amount.Value, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapper_FormmapTag(t *testing.T) {
+	type doc struct {
+		Name     string `formmap:"Title"`
+		Secret   string `formmap:"-"`
+		Quantity int    `formmap:",omitempty"`
+	}
+	type form struct {
+		Title    FormInputData
+		Name     FormInputData
+		Secret   FormInputData
+		Quantity FormInputData
+	}
+
+	mapper := NewMapper()
+
+	valErr := &ValidationError{Errors: Errors{"Name": ValidationField{Tag: "required"}}}
+	f := &form{}
+
+	if err := mapper.MapToForm(&doc{Name: "Mug", Secret: "s3cr3t", Quantity: 2}, valErr, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	if f.Title.Value != "Mug" || f.Title.Error != "This field is required" {
+		t.Errorf("Title = %+v, want value and error keyed by document path", f.Title)
+	}
+	if f.Name != (FormInputData{}) {
+		t.Errorf("Name = %+v, want untouched", f.Name)
+	}
+	if f.Secret.Value != "" {
+		t.Errorf("Secret = %q, want skipped", f.Secret.Value)
+	}
+	if f.Quantity.Value != "2" {
+		t.Errorf("Quantity = %q, want 2", f.Quantity.Value)
+	}
+
+	back := &doc{Secret: "kept"}
+	if err := mapper.MapFromForm(f, back); err != nil {
+		t.Fatalf("MapFromForm() error = %v", err)
+	}
+	if back.Name != "Mug" || back.Secret != "kept" || back.Quantity != 2 {
+		t.Errorf("MapFromForm() doc = %+v", back)
+	}
+}
+
+func TestMapper_WithJSONNames(t *testing.T) {
+	type address struct {
+		City string `json:"city"`
+	}
+	type doc struct {
+		FirstName string `json:"first_name"`
+		Email     string `json:"email,omitempty"`
+		Internal  string `json:"-"`
+		Untagged  string
+		Address   address   `json:"address"`
+		Lines     []address `json:"lines"`
+	}
+	type addressForm struct {
+		City FormInputData
+	}
+	type form struct {
+		FirstName FormInputData
+		Email     FormInputData
+		Internal  FormInputData
+		Untagged  FormInputData
+		Address   addressForm
+		Lines     []addressForm
+	}
+
+	mapper := NewMapper(WithJSONNames())
+
+	valErr := &ValidationError{Errors: Errors{
+		"first_name":    ValidationField{Tag: "required"},
+		"Untagged":      ValidationField{Tag: "email"},
+		"address.city":  ValidationField{Tag: "required"},
+		"lines[0].city": ValidationField{Tag: "min", Param: "2"},
+		"FirstName":     ValidationField{Tag: "max", Param: "1"},
+		"Address.City":  ValidationField{Tag: "max", Param: "1"},
+		"Lines[0].City": ValidationField{Tag: "max", Param: "1"},
+	}}
+
+	f := &form{}
+	d := &doc{FirstName: "Jo", Email: "jo@example.com", Internal: "x", Lines: []address{{City: "A"}}}
+
+	if err := mapper.MapToForm(d, valErr, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		got  FormInputData
+		want FormInputData
+	}{
+		{"FirstName", f.FirstName, FormInputData{Value: "Jo", Error: "This field is required"}},
+		{"Email", f.Email, FormInputData{Value: "jo@example.com"}},
+		{"Internal", f.Internal, FormInputData{}},
+		{"Untagged", f.Untagged, FormInputData{Error: "Invalid email address"}},
+		{"Address.City", f.Address.City, FormInputData{Error: "This field is required"}},
+		{"Lines[0].City", f.Lines[0].City, FormInputData{Value: "A", Error: "Must be at least 2 characters"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %+v, want %+v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapper_MapToForm_Maps(t *testing.T) {
+	type doc struct {
+		Attrs    map[string]string
+		Variants map[string]TestItem
+		Sizes    map[int]float64
+		Empty    map[string]string
+	}
+	type form struct {
+		Attrs    map[string]FormInputData
+		Variants map[string]TestItemForm
+		Sizes    map[string]FormInputData
+		Empty    map[string]FormInputData
+	}
+
+	mapper := NewMapper()
+
+	d := &doc{
+		Attrs:    map[string]string{"color": "red", "size": "L"},
+		Variants: map[string]TestItem{"small": {ItemID: "s1", Price: 5}},
+		Sizes:    map[int]float64{42: 1.5},
+	}
+	valErr := &ValidationError{Errors: Errors{
+		"Attrs[color]":             ValidationField{Tag: "oneof", Param: "blue green"},
+		"Variants[small].ItemName": ValidationField{Tag: "required"},
+	}}
+	f := &form{Empty: map[string]FormInputData{"stale": {Value: "x"}}}
+
+	if err := mapper.MapToForm(d, valErr, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	if got := f.Attrs["color"]; got.Value != "red" || got.Error != "Must be one of: blue, green" {
+		t.Errorf("Attrs[color] = %+v", got)
+	}
+	if got := f.Attrs["size"]; got.Value != "L" || got.Error != "" {
+		t.Errorf("Attrs[size] = %+v", got)
+	}
+	if got := f.Variants["small"]; got.ItemID.Value != "s1" || got.Price.Value != "5" || got.ItemName.Error != "This field is required" {
+		t.Errorf("Variants[small] = %+v", got)
+	}
+	if got := f.Sizes["42"]; got.Value != "1.5" {
+		t.Errorf("Sizes[42] = %+v", got)
+	}
+	if f.Empty != nil {
+		t.Errorf("Empty = %v, want nil", f.Empty)
+	}
+
+	back := &doc{}
+	if err := mapper.MapFromForm(f, back); err != nil {
+		t.Fatalf("MapFromForm() error = %v", err)
+	}
+	if !reflect.DeepEqual(back, d) {
+		t.Errorf("MapFromForm() = %+v, want %+v", back, d)
+	}
+}
+
+func TestMapper_MapToForm_Arrays(t *testing.T) {
+	type doc struct {
+		Codes [4]string
+		Items [2]TestItem
+		Pair  [2]int
+	}
+	type form struct {
+		Codes []FormInputData
+		Items []TestItemForm
+		Pair  [2]FormInputData
+	}
+
+	mapper := NewMapper()
+
+	d := &doc{
+		Codes: [4]string{"a", "b", "", "d"},
+		Items: [2]TestItem{{ItemID: "1"}, {ItemID: "2", Price: 3}},
+		Pair:  [2]int{7, 9},
+	}
+	valErr := &ValidationError{Errors: Errors{
+		"Codes[2]":       ValidationField{Tag: "required"},
+		"Items[1].Price": ValidationField{Tag: "lt", Param: "3"},
+		"Pair[1]":        ValidationField{Tag: "lte", Param: "8"},
+	}}
+	f := &form{}
+
+	if err := mapper.MapToForm(d, valErr, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	if len(f.Codes) != 4 || f.Codes[3].Value != "d" || f.Codes[2].Error != "This field is required" {
+		t.Errorf("Codes = %+v", f.Codes)
+	}
+	if len(f.Items) != 2 || f.Items[1].ItemID.Value != "2" || f.Items[1].Price.Error != "Value must be less than 3" {
+		t.Errorf("Items = %+v", f.Items)
+	}
+	if f.Pair[0].Value != "7" || f.Pair[1].Error != "Value must be at most 8" {
+		t.Errorf("Pair = %+v", f.Pair)
+	}
+
+	back := &doc{}
+	if err := mapper.MapFromForm(f, back); err != nil {
+		t.Fatalf("MapFromForm() error = %v", err)
+	}
+	if *back != *d {
+		t.Errorf("MapFromForm() = %+v, want %+v", back, d)
+	}
+}
+
+func TestMapper_MapToForm_InterfaceFields(t *testing.T) {
+	type doc struct {
+		Payload  any
+		Items    any
+		Count    any
+		Pointer  any
+		Nothing  any
+		Elements []any
+	}
+	type form struct {
+		Payload  TestMetadataForm
+		Items    []TestItemForm
+		Count    FormInputData
+		Pointer  TestMetadataForm
+		Nothing  TestMetadataForm
+		Elements []TestItemForm
+	}
+
+	mapper := NewMapper()
+
+	d := &doc{
+		Payload:  TestMetadata{Version: "2.0", Author: "Ann"},
+		Items:    []TestItem{{ItemID: "a"}, {ItemID: "b"}},
+		Count:    42,
+		Pointer:  &TestMetadata{Author: "Bob"},
+		Elements: []any{TestItem{ItemID: "x"}, &TestItem{ItemID: "y"}},
+	}
+	valErr := &ValidationError{Errors: Errors{
+		"Payload.Author":  ValidationField{Tag: "min", Param: "5"},
+		"Items[1].ItemID": ValidationField{Tag: "uuid"},
+	}}
+	f := &form{}
+
+	if err := mapper.MapToForm(d, valErr, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	if f.Payload.Version.Value != "2.0" || f.Payload.Author.Error != "Must be at least 5 characters" {
+		t.Errorf("Payload = %+v", f.Payload)
+	}
+	if len(f.Items) != 2 || f.Items[1].ItemID.Value != "b" || f.Items[1].ItemID.Error != "Invalid UUID" {
+		t.Errorf("Items = %+v", f.Items)
+	}
+	if f.Count.Value != "42" {
+		t.Errorf("Count = %+v", f.Count)
+	}
+	if f.Pointer.Author.Value != "Bob" {
+		t.Errorf("Pointer = %+v", f.Pointer)
+	}
+	if f.Nothing != (TestMetadataForm{}) {
+		t.Errorf("Nothing = %+v, want zero", f.Nothing)
+	}
+	if len(f.Elements) != 2 || f.Elements[0].ItemID.Value != "x" || f.Elements[1].ItemID.Value != "y" {
+		t.Errorf("Elements = %+v", f.Elements)
+	}
+}
+
+func TestMapper_ConcurrentUse(t *testing.T) {
+	mapper := NewMapper()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+
+		go func(i int) {
+			defer wg.Done()
+			mapper.RegisterConverter(reflect.TypeOf(time.Duration(0)), func(v reflect.Value) string {
+				return v.Interface().(time.Duration).String()
+			})
+			mapper.RegisterFieldMapper("Items["+strconv.Itoa(i)+"].Price", func(docField, formField reflect.Value, fieldPath string, valErr *ValidationError) error {
+				return nil
+			})
+		}(i)
+
+		go func() {
+			defer wg.Done()
+			formData := &TestFormData{}
+			if err := mapper.MapToForm(&TestDocument{Name: "n", Duration: time.Minute}, nil, formData); err != nil {
+				t.Errorf("MapToForm() error = %v", err)
+			}
+			if formData.Name.Value != "n" {
+				t.Errorf("Name value = %q, want n", formData.Name.Value)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestMapper_MapToFormWithOptions_SkipFields(t *testing.T) {
+	mapper := NewMapper()
+
+	doc := &TestDocument{
+		Name:     "Widget",
+		Price:    10,
+		Metadata: TestMetadata{Version: "1", Author: "Ann"},
+		Items: []TestItem{
+			{ItemID: "1", Price: 1},
+			{ItemID: "2", Price: 2},
+		},
+		Tags: []string{"a", "b"},
+	}
+
+	formData := &TestFormData{}
+	opts := MapOptions{
+		SkipFields: []string{"Price", "Metadata.*", "Items[*].Price", "Tags[1]"},
+	}
+
+	if err := mapper.MapToFormWithOptions(doc, nil, formData, opts); err != nil {
+		t.Fatalf("MapToFormWithOptions() error = %v", err)
+	}
+
+	if formData.Name.Value != "Widget" {
+		t.Errorf("Name value = %q, want Widget", formData.Name.Value)
+	}
+	if formData.Price.Value != "" {
+		t.Errorf("Price value = %q, want skipped", formData.Price.Value)
+	}
+	if formData.Metadata != (TestMetadataForm{}) {
+		t.Errorf("Metadata = %+v, want skipped", formData.Metadata)
+	}
+	for i, item := range formData.Items {
+		if item.ItemID.Value == "" || item.Price.Value != "" {
+			t.Errorf("Items[%d] = %+v, want ItemID only", i, item)
+		}
+	}
+	if len(formData.Tags) != 2 || formData.Tags[0].Value != "a" || formData.Tags[1].Value != "" {
+		t.Errorf("Tags = %+v, want second tag skipped", formData.Tags)
+	}
+}
+
+func TestMapper_MapToFormWithOptions_Limits(t *testing.T) {
+	mapper := NewMapper()
+	doc := &TestDocument{
+		Name:  "Widget",
+		Tags:  []string{"a", "b", "c"},
+		Items: []TestItem{{ItemID: "1"}, {ItemID: "2"}},
+	}
+
+	tests := []struct {
+		name    string
+		opts    MapOptions
+		path    string
+		wantErr bool
+	}{
+		{"within limits", MapOptions{MaxFields: 100, MaxSliceLen: 3, MaxDepth: 3}, "", false},
+		{"long slice", MapOptions{MaxSliceLen: 2}, "field Tags:", true},
+		{"deep nesting", MapOptions{MaxDepth: 2}, "field Items[0]:", true},
+		{"too many fields", MapOptions{MaxFields: 20}, "field Items[1]:", true},
+		{"collecting errors still aborts", MapOptions{MaxSliceLen: 1, CollectErrors: true}, "field Tags:", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := mapper.MapToFormWithOptions(doc, nil, &TestFormData{}, tt.opts)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("MapToFormWithOptions() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrLimitExceeded) {
+				t.Fatalf("MapToFormWithOptions() error = %v, want ErrLimitExceeded", err)
+			}
+			if !strings.HasPrefix(err.Error(), tt.path) {
+				t.Errorf("error = %q, want it to start with %q", err, tt.path)
+			}
+		})
+	}
+
+	if err := mapper.MapToFormWithOptions(doc, nil, &TestFormData{}, MapOptions{MaxDepth: -1}); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("negative limit error = %v, want ErrInvalidOption", err)
+	}
+}
+
+func TestMapper_RegisterPathConverter(t *testing.T) {
+	mapper := NewMapper()
+	mapper.RegisterPathConverter("Items[*].Price", func(v reflect.Value) string {
+		return "$" + strconv.FormatFloat(v.Float(), 'f', 2, 64)
+	})
+
+	doc := &TestDocument{
+		Price: 5,
+		Items: []TestItem{{Price: 1}, {Price: 2.5}},
+	}
+	valErr := &ValidationError{Errors: Errors{"Items[1].Price": ValidationField{Tag: "required"}}}
+	formData := &TestFormData{}
+
+	if err := mapper.MapToForm(doc, valErr, formData); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	if formData.Price.Value != "5" {
+		t.Errorf("Price value = %q, want type converter output 5", formData.Price.Value)
+	}
+	if formData.Items[0].Price.Value != "$1.00" || formData.Items[1].Price.Value != "$2.50" {
+		t.Errorf("Items prices = %q, %q", formData.Items[0].Price.Value, formData.Items[1].Price.Value)
+	}
+	if formData.Items[1].Price.Error != "This field is required" {
+		t.Errorf("Items[1].Price error = %q", formData.Items[1].Price.Error)
+	}
+}
+
+func TestMapper_Unregister(t *testing.T) {
+	mapper := NewMapper()
+	mapper.RegisterConverter(reflect.TypeOf(""), func(v reflect.Value) string { return "conv" })
+	mapper.RegisterPathConverter("Price", func(v reflect.Value) string { return "path" })
+	mapper.RegisterSiblingFieldMapper("Description", func(_, _, formField reflect.Value, _ string, _ *ValidationError) error {
+		formField.FieldByName("Value").SetString("sibling")
+		return nil
+	})
+
+	mapper.UnregisterConverter(reflect.TypeOf(""))
+	mapper.UnregisterFieldMapper("Price")
+	mapper.UnregisterFieldMapper("Description")
+
+	doc := &TestDocument{Name: "Go", Price: 2.5, Description: "active"}
+	formData := &TestFormData{}
+	if err := mapper.MapToForm(doc, nil, formData); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+	if formData.Name.Value != "Go" || formData.Price.Value != "2.5" || formData.Description.Value != "active" {
+		t.Errorf("form = %+v", formData)
+	}
+}
+
+func TestMapper_ResetDefaults(t *testing.T) {
+	mapper := NewMapper(WithTimeLayout("2006-01-02"))
+	mapper.RegisterConverter(reflect.TypeOf(float64(0)), func(v reflect.Value) string { return "custom" })
+	mapper.RegisterFieldMapper("Name", func(_, formField reflect.Value, _ string, _ *ValidationError) error {
+		formField.FieldByName("Value").SetString("mapped")
+		return nil
+	})
+	mapper.RegisterFilter(reflect.TypeOf(""), Truncate(1))
+	mapper.AfterMap(func(doc, form any, valErr *ValidationError) error {
+		return errors.New("hook")
+	})
+
+	mapper.ResetDefaults()
+
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	doc := &TestDocument{Name: "Go", Price: 2.5, CreatedAt: created}
+	formData := &TestFormData{}
+	if err := mapper.MapToForm(doc, nil, formData); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+	if formData.Name.Value != "Go" || formData.Price.Value != "2.5" {
+		t.Errorf("form = %+v", formData)
+	}
+	if formData.CreatedAt.Value != "2024-01-02" {
+		t.Errorf("CreatedAt = %q, want the layout option to survive", formData.CreatedAt.Value)
+	}
+}
+
+func TestMapper_ResetDefaults_Concurrent(t *testing.T) {
+	mapper := NewMapper(WithTimeLayout("2006-01-02"))
+	doc := &TestDocument{Name: "Go", CreatedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for range 200 {
+			mapper.ResetDefaults()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for range 200 {
+			formData := &TestFormData{}
+			if err := mapper.MapToForm(doc, nil, formData); err != nil {
+				t.Errorf("MapToForm() error = %v", err)
+				return
+			}
+			// A half-reset mapper would lack the time converter.
+			if formData.CreatedAt.Value != "2024-01-02" {
+				t.Errorf("CreatedAt = %q during a reset", formData.CreatedAt.Value)
+				return
+			}
+		}
+	}()
+	wg.Wait()
+
+	mapper.ResetDefaults()
+	empty := true
+	mapper.plans.Range(func(_, _ any) bool {
+		empty = false
+		return false
+	})
+	if !empty {
+		t.Error("ResetDefaults kept the cached struct plans")
+	}
+}
+
+func TestMapper_RegisterFieldMapper_Wildcards(t *testing.T) {
+	mapper := NewMapper()
+
+	price := func(prefix string) FieldMapper {
+		return func(docField, formField reflect.Value, fieldPath string, valErr *ValidationError) error {
+			formField.FieldByName("Value").SetString(prefix + strconv.FormatFloat(docField.Float(), 'f', 2, 64))
+			return nil
+		}
+	}
+
+	mapper.RegisterFieldMapper("**.Price", price("any:"))
+	mapper.RegisterFieldMapper("Items[*].Price", price("item:"))
+	mapper.RegisterFieldMapper("Items[1].Price", price("second:"))
+	mapper.RegisterFieldMapper("Tags[*]", func(docField, formField reflect.Value, fieldPath string, valErr *ValidationError) error {
+		formField.FieldByName("Value").SetString("#" + docField.String())
+		return nil
+	})
+
+	doc := &TestDocument{
+		Price: 5,
+		Items: []TestItem{{Price: 1}, {Price: 2}, {Price: 3}},
+		Tags:  []string{"go"},
+	}
+	formData := &TestFormData{}
+
+	if err := mapper.MapToForm(doc, nil, formData); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"Price", formData.Price.Value, "any:5.00"},
+		{"Items[0].Price", formData.Items[0].Price.Value, "item:1.00"},
+		{"Items[1].Price", formData.Items[1].Price.Value, "second:2.00"},
+		{"Items[2].Price", formData.Items[2].Price.Value, "item:3.00"},
+		{"Tags[0]", formData.Tags[0].Value, "#go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapper_MapToFormWithOptions_WildcardConverters(t *testing.T) {
+	mapper := NewMapper()
+
+	doc := &TestDocument{Items: []TestItem{{Price: 1}, {Price: 2}}}
+	formData := &TestFormData{}
+	opts := MapOptions{
+		FieldConverters: map[string]ValueConverter{
+			"Items[*].Price": func(v reflect.Value) string {
+				return "$" + strconv.FormatFloat(v.Float(), 'f', 2, 64)
+			},
+		},
+	}
+
+	if err := mapper.MapToFormWithOptions(doc, nil, formData, opts); err != nil {
+		t.Fatalf("MapToFormWithOptions() error = %v", err)
+	}
+
+	if formData.Items[0].Price.Value != "$1.00" || formData.Items[1].Price.Value != "$2.00" {
+		t.Errorf("Items = %+v", formData.Items)
+	}
+}
+
+type testStatus int
+
+func (s testStatus) String() string {
+	return [...]string{"unknown", "active", "archived"}[s]
+}
+
+type testSKU struct {
+	prefix string
+	n      int
+}
+
+func (s *testSKU) MarshalText() ([]byte, error) {
+	return []byte(s.prefix + "-" + strconv.Itoa(s.n)), nil
+}
+
+func (s testSKU) String() string {
+	return "stringer should lose to MarshalText"
+}
+
+func TestMapper_StringerAndTextMarshaler(t *testing.T) {
+	type doc struct {
+		Status testStatus
+		SKU    testSKU
+		Addr   netip.Addr
+	}
+	type form struct {
+		Status FormInputData
+		SKU    FormInputData
+		Addr   FormInputData
+	}
+
+	mapper := NewMapper()
+	f := &form{}
+
+	d := &doc{Status: 2, SKU: testSKU{prefix: "AB", n: 7}, Addr: netip.MustParseAddr("10.0.0.1")}
+	if err := mapper.MapToForm(d, nil, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	if f.Status.Value != "archived" {
+		t.Errorf("Status = %q, want archived", f.Status.Value)
+	}
+	if f.SKU.Value != "AB-7" {
+		t.Errorf("SKU = %q, want AB-7", f.SKU.Value)
+	}
+	if f.Addr.Value != "10.0.0.1" {
+		t.Errorf("Addr = %q, want 10.0.0.1", f.Addr.Value)
+	}
+}
+
+func TestRegisterConverterFor(t *testing.T) {
+	mapper := NewMapper()
+
+	RegisterConverterFor(mapper, func(d time.Duration) string {
+		return d.String()
+	})
+
+	formData := &TestFormData{}
+	if err := mapper.MapToForm(&TestDocument{Duration: 90 * time.Second}, nil, formData); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	if formData.Duration.Value != "1m30s" {
+		t.Errorf("Duration value = %q, want 1m30s", formData.Duration.Value)
+	}
+}
+
+func TestParseFormmapTag(t *testing.T) {
+	tests := []struct {
+		tag        string
+		wantName   string
+		wantLayout string
+	}{
+		{"Title", "Title", ""},
+		{"-", "-", ""},
+		{",omitempty", "", ""},
+		{"layout=2006-01-02", "", "2006-01-02"},
+		{"When,layout=2006-01-02T15:04", "When", "2006-01-02T15:04"},
+		{"layout=Jan 2, 2006", "", "Jan 2, 2006"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			name, fc := parseFormmapTag(tt.tag)
+			if name != tt.wantName || fc.TimeLayout != tt.wantLayout {
+				t.Errorf("parseFormmapTag(%q) = (%q, %q), want (%q, %q)", tt.tag, name, fc.TimeLayout, tt.wantName, tt.wantLayout)
+			}
+		})
+	}
+}
+
+func TestMapper_TimeLayoutTag(t *testing.T) {
+	type event struct {
+		Day      time.Time `formmap:"layout=2006-01-02"`
+		StartsAt time.Time `formmap:"layout=2006-01-02T15:04"`
+		Created  time.Time
+		Holidays []time.Time `formmap:"layout=Jan 2, 2006"`
+	}
+	type eventForm struct {
+		Day      FormInputData
+		StartsAt FormInputData
+		Created  FormInputData
+		Holidays []FormInputData
+	}
+
+	mapper := NewMapper()
+	ts := time.Date(2024, 1, 1, 9, 30, 0, 0, time.UTC)
+
+	d := &event{Day: ts, StartsAt: ts, Created: ts, Holidays: []time.Time{ts}}
+	f := &eventForm{}
+
+	if err := mapper.MapToForm(d, nil, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	if f.Day.Value != "2024-01-01" {
+		t.Errorf("Day = %q, want 2024-01-01", f.Day.Value)
+	}
+	if f.StartsAt.Value != "2024-01-01T09:30" {
+		t.Errorf("StartsAt = %q, want 2024-01-01T09:30", f.StartsAt.Value)
+	}
+	if f.Created.Value != "2024-01-01T09:30:00Z" {
+		t.Errorf("Created = %q, want RFC3339", f.Created.Value)
+	}
+	if f.Holidays[0].Value != "Jan 1, 2024" {
+		t.Errorf("Holidays[0] = %q, want Jan 1, 2024", f.Holidays[0].Value)
+	}
+
+	back := &event{}
+	if err := mapper.MapFromForm(f, back); err != nil {
+		t.Fatalf("MapFromForm() error = %v", err)
+	}
+	if !back.StartsAt.Equal(ts) || !back.Day.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("MapFromForm() = %+v", back)
+	}
+}
+
+func TestMapper_DurationUnit(t *testing.T) {
+	type timer struct {
+		Default time.Duration
+		Seconds time.Duration `formmap:"unit=seconds"`
+		Go      time.Duration `formmap:"unit=string"`
+	}
+	type timerForm struct {
+		Default FormInputData
+		Seconds FormInputData
+		Go      FormInputData
+	}
+
+	d := &timer{Default: 90 * time.Minute, Seconds: 90 * time.Second, Go: 90 * time.Minute}
+
+	tests := []struct {
+		name        string
+		opts        []MapperOption
+		wantDefault string
+	}{
+		{"minutes by default", nil, "90"},
+		{"hours", []MapperOption{WithDurationUnit("hours")}, "1.5"},
+		{"go string", []MapperOption{WithDurationUnit("string")}, "1h30m0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapper := NewMapper(tt.opts...)
+			f := &timerForm{}
+			if err := mapper.MapToForm(d, nil, f); err != nil {
+				t.Fatalf("MapToForm() error = %v", err)
+			}
+
+			if f.Default.Value != tt.wantDefault {
+				t.Errorf("Default = %q, want %q", f.Default.Value, tt.wantDefault)
+			}
+			if f.Seconds.Value != "90" {
+				t.Errorf("Seconds = %q, want 90", f.Seconds.Value)
+			}
+			if f.Go.Value != "1h30m0s" {
+				t.Errorf("Go = %q, want 1h30m0s", f.Go.Value)
+			}
+
+			back := &timer{}
+			if err := mapper.MapFromForm(f, back); err != nil {
+				t.Fatalf("MapFromForm() error = %v", err)
+			}
+			if *back != *d {
+				t.Errorf("MapFromForm() = %+v, want %+v", *back, *d)
+			}
+		})
+	}
+
+	if err := NewMapper(WithDurationUnit("days")).MapToForm(d, nil, &timerForm{}); err == nil {
+		t.Error("expected error for unknown duration unit")
+	}
+
+	// A misspelled unit= option fails rather than falling back to minutes.
+	type typo struct {
+		Wait time.Duration `formmap:"unit=secs"`
+	}
+	type typoForm struct{ Wait FormInputData }
+	mapper := NewMapper()
+	if _, err := mapper.Compile(reflect.TypeOf(typo{}), reflect.TypeOf(typoForm{})); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("Compile() error = %v, want ErrInvalidOption", err)
+	}
+	f := &typoForm{}
+	if err := mapper.MapToForm(&typo{Wait: time.Minute}, nil, f); !errors.Is(err, ErrInvalidOption) || f.Wait.Value != "" {
+		t.Errorf("MapToForm() error = %v, Wait = %q; want ErrInvalidOption", err, f.Wait.Value)
+	}
+	if err := mapper.MapFromForm(&typoForm{Wait: FormInputData{Value: "5"}}, &typo{}); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("MapFromForm() error = %v, want ErrInvalidOption", err)
+	}
+}
+
+// testUUID mirrors github.com/google/uuid.UUID: a [16]byte array with text
+// marshalling methods.
+type testUUID [16]byte
+
+func (u testUUID) String() string {
+	b := hex.EncodeToString(u[:])
+	return b[:8] + "-" + b[8:12] + "-" + b[12:16] + "-" + b[16:20] + "-" + b[20:]
+}
+
+func (u testUUID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+func (u *testUUID) UnmarshalText(text []byte) error {
+	s := string(text)
+	if len(s) != 36 {
+		return errors.New("invalid UUID length")
+	}
+	s = s[:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	_, err := hex.Decode(u[:], []byte(s))
+	return err
+}
+
+func TestMapper_UUIDRoundTrip(t *testing.T) {
+	type account struct {
+		ID      testUUID
+		Owner   *testUUID
+		Members []testUUID
+		Parent  testUUID
+	}
+	type accountForm struct {
+		ID      FormInputData
+		Owner   FormInputData
+		Members []FormInputData
+		Parent  FormInputData
+	}
+
+	id := testUUID{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	d := &account{ID: id, Owner: &id, Members: []testUUID{id}}
+
+	mapper := NewMapper()
+	f := &accountForm{}
+	if err := mapper.MapToForm(d, nil, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	const want = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	if f.ID.Value != want || f.Owner.Value != want || f.Members[0].Value != want {
+		t.Errorf("MapToForm() = %+v, want %s everywhere", f, want)
+	}
+	if f.Parent.Value != "" {
+		t.Errorf("Parent = %q, want empty for the nil UUID", f.Parent.Value)
+	}
+
+	back := &account{}
+	if err := mapper.MapFromForm(f, back); err != nil {
+		t.Fatalf("MapFromForm() error = %v", err)
+	}
+	if back.ID != id || *back.Owner != id || back.Members[0] != id || back.Parent != (testUUID{}) {
+		t.Errorf("MapFromForm() = %+v", back)
+	}
+}
+
+func TestMapper_TimeZone(t *testing.T) {
+	type meeting struct {
+		StartsAt time.Time `formmap:"layout=2006-01-02T15:04"`
+		EndsAt   *time.Time
+		Created  time.Time
+	}
+	type meetingForm struct {
+		StartsAt FormInputData
+		EndsAt   FormInputData
+		Created  FormInputData
+	}
+
+	cairo := time.FixedZone("EET", 2*60*60)
+	start := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	d := &meeting{StartsAt: start, EndsAt: &end}
+
+	mapper := NewMapper(WithTimeZone(cairo))
+	f := &meetingForm{}
+	if err := mapper.MapToForm(d, nil, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	if f.StartsAt.Value != "2024-03-01T10:00" {
+		t.Errorf("StartsAt = %q, want 2024-03-01T10:00", f.StartsAt.Value)
+	}
+	if f.EndsAt.Value != "2024-03-01T11:00:00+02:00" {
+		t.Errorf("EndsAt = %q, want 2024-03-01T11:00:00+02:00", f.EndsAt.Value)
+	}
+	if f.Created.Value != "" {
+		t.Errorf("Created = %q, want empty", f.Created.Value)
+	}
+
+	back := &meeting{}
+	if err := mapper.MapFromForm(f, back); err != nil {
+		t.Fatalf("MapFromForm() error = %v", err)
+	}
+	if back.StartsAt != start || *back.EndsAt != end || !back.Created.IsZero() {
+		t.Errorf("MapFromForm() = %v, %v, %v; want UTC %v, %v", back.StartsAt, back.EndsAt, back.Created, start, end)
+	}
+
+	f = &meetingForm{}
+	err := mapper.MapToFormWithOptions(d, nil, f, MapOptions{TimeZone: time.UTC})
+	if err != nil {
+		t.Fatalf("MapToFormWithOptions() error = %v", err)
+	}
+	if f.StartsAt.Value != "2024-03-01T08:00" {
+		t.Errorf("StartsAt with UTC override = %q, want 2024-03-01T08:00", f.StartsAt.Value)
+	}
+}
+
+func TestMapper_TimeZone_ExplicitOffset(t *testing.T) {
+	type meeting struct {
+		StartsAt time.Time
+		EndsAt   time.Time
+		Local    time.Time
+	}
+	type meetingForm struct {
+		StartsAt FormInputData
+		EndsAt   FormInputData
+		Local    FormInputData
+	}
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	f := &meetingForm{
+		StartsAt: FormInputData{Value: "2024-01-01T10:00:00Z"},
+		EndsAt:   FormInputData{Value: "2024-01-01T12:00:00+02:00"},
+		Local:    FormInputData{Value: "2024-01-01T10:00"},
+	}
+
+	back := &meeting{}
+	if err := NewMapper(WithTimeZone(berlin)).MapFromForm(f, back); err != nil {
+		t.Fatalf("MapFromForm() error = %v", err)
+	}
+
+	want := meeting{
+		StartsAt: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+		EndsAt:   time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+		// Berlin is UTC+1 in winter.
+		Local: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+	}
+	if *back != want {
+		t.Errorf("MapFromForm() = %+v, want %+v", *back, want)
+	}
+}
+
+func TestMapper_WithTimeLayout(t *testing.T) {
+	type post struct{ Published time.Time }
+	type postForm struct{ Published FormInputData }
+
+	const layout = "02/01/2006 15:04"
+	mapper := NewMapper(WithTimeLayout(layout))
+	if mapper.TimeLayout() != layout {
+		t.Errorf("TimeLayout() = %q, want %q", mapper.TimeLayout(), layout)
+	}
+	if got := NewMapper().TimeLayout(); got != time.RFC3339 {
+		t.Errorf("default TimeLayout() = %q, want RFC3339", got)
+	}
+
+	ts := time.Date(2024, 12, 25, 18, 30, 0, 0, time.UTC)
+	f := &postForm{}
+	if err := mapper.MapToForm(&post{Published: ts}, nil, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+	if f.Published.Value != "25/12/2024 18:30" {
+		t.Errorf("Published = %q, want 25/12/2024 18:30", f.Published.Value)
+	}
+
+	back := &post{}
+	if err := mapper.MapFromForm(f, back); err != nil {
+		t.Fatalf("MapFromForm() error = %v", err)
+	}
+	if !back.Published.Equal(ts) {
+		t.Errorf("Published = %v, want %v", back.Published, ts)
+	}
+}
+
+type richInput struct {
+	Val      string
+	Errors   []string
+	Label    string
+	Required bool
+}
+
+func (r richInput) FormValue() string { return r.Val }
+
+func (r *richInput) SetValue(value string) { r.Val = value }
+
+func (r *richInput) SetError(msg string) {
+	r.Errors = nil
+	if msg != "" {
+		r.Errors = []string{msg}
+	}
+}
+
+func TestMapper_CustomFormField(t *testing.T) {
+	type user struct {
+		Name   string
+		Emails []string
+		Meta   map[string]int
+	}
+	type userForm struct {
+		Name   richInput
+		Emails []richInput
+		Meta   map[string]richInput
+	}
+
+	mapper := NewMapper()
+	if err := mapper.ApplyConfig(&Config{Fields: map[string]FieldConfig{"Name": {Label: "Full name"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	valErr := &ValidationError{Errors: Errors{"Name": ValidationField{Tag: "required"}}}
+	d := &user{Name: "Ada", Emails: []string{"ada@example.com"}, Meta: map[string]int{"age": 36}}
+	f := &userForm{}
+	if err := mapper.MapToForm(d, valErr, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	if f.Name.Val != "Ada" || len(f.Name.Errors) != 1 || f.Name.Errors[0] != "This field is required" || f.Name.Label != "Full name" {
+		t.Errorf("Name = %+v", f.Name)
+	}
+	if f.Emails[0].Val != "ada@example.com" || f.Emails[0].Errors != nil {
+		t.Errorf("Emails[0] = %+v", f.Emails[0])
+	}
+	if f.Meta["age"].Val != "36" {
+		t.Errorf("Meta[age] = %+v", f.Meta["age"])
+	}
+
+	back := &user{}
+	if err := mapper.MapFromForm(f, back); err != nil {
+		t.Fatalf("MapFromForm() error = %v", err)
+	}
+	if back.Name != "Ada" || back.Emails[0] != "ada@example.com" || back.Meta["age"] != 36 {
+		t.Errorf("MapFromForm() = %+v", back)
+	}
+}
+
+func TestMapper_FormFieldTypeIdentity(t *testing.T) {
+	// otherInput has FormInputData's shape but none of its methods, like a
+	// same-named type from another package.
+	type otherInput struct {
+		Value string
+		Error string
+	}
+	type aliasInput = FormInputData
+
+	type user struct {
+		Name  string
+		Email string
+		Tags  []string
+	}
+	type userForm struct {
+		Name  otherInput
+		Email aliasInput
+		Tags  []otherInput
+	}
+
+	d := &user{Name: "Ada", Email: "ada@example.com", Tags: []string{"x"}}
+	valErr := &ValidationError{Errors: Errors{"Name": ValidationField{Tag: "required"}}}
+
+	mapper := NewMapper()
+	f := &userForm{}
+	if err := mapper.MapToForm(d, valErr, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+	if f.Name.Value != "" || f.Tags[0].Value != "" {
+		t.Errorf("unregistered look-alike type was filled: %+v", f)
+	}
+	if f.Email.Value != "ada@example.com" {
+		t.Errorf("Email = %q, aliases of FormInputData should be leaves", f.Email.Value)
+	}
+
+	if err := mapper.RegisterFormFieldType(reflect.TypeOf(otherInput{})); err != nil {
+		t.Fatalf("RegisterFormFieldType() error = %v", err)
+	}
+	if err := mapper.MapToForm(d, valErr, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+	if f.Name.Value != "Ada" || f.Name.Error != "This field is required" || f.Tags[0].Value != "x" {
+		t.Errorf("registered type not filled: %+v", f)
+	}
+
+	back := &user{}
+	if err := mapper.MapFromForm(f, back); err != nil {
+		t.Fatalf("MapFromForm() error = %v", err)
+	}
+	if !reflect.DeepEqual(back, d) {
+		t.Errorf("MapFromForm() = %+v", back)
+	}
+
+	if err := mapper.RegisterFormFieldType(reflect.TypeOf(struct{ Value int }{})); err == nil {
+		t.Error("expected error for type without string Value and Error fields")
+	}
+}
+
+func TestMapper_SliceMerge(t *testing.T) {
+	type item struct{ Name string }
+	type itemForm struct {
+		Name     FormInputData
+		Expanded bool
+	}
+	type order struct{ Items []item }
+	type orderForm struct{ Items []itemForm }
+
+	d := &order{Items: []item{{"a"}, {"b"}, {"c"}}}
+	// The result mustn't depend on whether the slice grows in place.
+	forms := map[string]func() *orderForm{
+		"full": func() *orderForm {
+			return &orderForm{Items: []itemForm{{Expanded: true}, {Expanded: true}}[:2:2]}
+		},
+		"spare capacity": func() *orderForm {
+			return &orderForm{Items: []itemForm{{Expanded: true}, {Expanded: true}, {Expanded: true}, {}}[:2]}
+		},
+		"shrinking": func() *orderForm {
+			return &orderForm{Items: []itemForm{{Expanded: true}, {Expanded: true}, {Expanded: false}, {Expanded: true}}}
+		},
+	}
+
+	tests := []struct {
+		name     string
+		mapper   *Mapper
+		opts     MapOptions
+		wantKept bool
+	}{
+		{"replace by default", NewMapper(), MapOptions{}, false},
+		{"mapper option", NewMapper(WithSliceMerge()), MapOptions{}, true},
+		{"per call", NewMapper(), MapOptions{MergeSlices: true}, true},
+	}
+
+	for _, tt := range tests {
+		for formName, newForm := range forms {
+			t.Run(tt.name+"/"+formName, func(t *testing.T) {
+				f := newForm()
+				if err := tt.mapper.MapToFormWithOptions(d, nil, f, tt.opts); err != nil {
+					t.Fatalf("MapToFormWithOptions() error = %v", err)
+				}
+
+				if len(f.Items) != 3 || f.Items[2].Name.Value != "c" {
+					t.Fatalf("Items = %+v", f.Items)
+				}
+				for i := 0; i < 2; i++ {
+					if f.Items[i].Expanded != tt.wantKept {
+						t.Errorf("Items[%d].Expanded = %v, want %v", i, f.Items[i].Expanded, tt.wantKept)
+					}
+				}
+				if f.Items[2].Expanded {
+					t.Error("Items[2].Expanded should start fresh")
+				}
+			})
+		}
+
+		// A slice as long as the document isn't resized, so nothing is lost.
+		t.Run(tt.name+"/same length", func(t *testing.T) {
+			f := &orderForm{Items: []itemForm{{Expanded: true}, {Expanded: true}, {Expanded: true}}}
+			if err := tt.mapper.MapToFormWithOptions(d, nil, f, tt.opts); err != nil {
+				t.Fatalf("MapToFormWithOptions() error = %v", err)
+			}
+			for i, item := range f.Items {
+				if !item.Expanded || item.Name.Value != d.Items[i].Name {
+					t.Errorf("Items[%d] = %+v, want mapped with Expanded kept", i, item)
+				}
+			}
+		})
+	}
+}
+
+func TestMapper_CollectErrors(t *testing.T) {
+	type item struct{ Name string }
+	type doc struct {
+		A     string
+		B     string
+		Items []item
+		C     string
+	}
+	type form struct {
+		A     FormInputData
+		B     FormInputData
+		Items []struct{ Name FormInputData }
+		C     FormInputData
+	}
+
+	errBad := errors.New("bad")
+	newMapper := func(opts ...MapperOption) *Mapper {
+		m := NewMapper(opts...)
+		for _, path := range []string{"A", "Items[*].Name"} {
+			m.RegisterFieldMapper(path, func(docField, formField reflect.Value, fieldPath string, valErr *ValidationError) error {
+				return errBad
+			})
+		}
+		return m
+	}
+
+	d := &doc{A: "a", B: "b", Items: []item{{"x"}, {"y"}}, C: "c"}
+
+	f := &form{}
+	err := newMapper().MapToForm(d, nil, f)
+	if !errors.Is(err, errBad) {
+		t.Fatalf("MapToForm() error = %v, want %v", err, errBad)
+	}
+	if f.B.Value != "" {
+		t.Errorf("fail-fast mapping continued past A: %+v", f)
+	}
+
+	for name, run := range map[string]func(f *form) error{
+		"mapper option": func(f *form) error { return newMapper(WithCollectErrors()).MapToForm(d, nil, f) },
+		"per call": func(f *form) error {
+			return newMapper().MapToFormWithOptions(d, nil, f, MapOptions{CollectErrors: true})
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			f := &form{}
+			err := run(f)
+			if !errors.Is(err, errBad) {
+				t.Fatalf("error = %v, want %v", err, errBad)
+			}
+			if f.B.Value != "b" || f.C.Value != "c" || len(f.Items) != 2 {
+				t.Errorf("mapping stopped early: %+v", f)
+			}
+
+			joined, ok := err.(interface{ Unwrap() []error })
+			if !ok || len(joined.Unwrap()) != 3 {
+				t.Fatalf("error = %v, want 3 joined errors", err)
+			}
+			var paths []string
+			for _, e := range joined.Unwrap() {
+				var fe *FieldError
+				if !errors.As(e, &fe) || !errors.Is(e, ErrFieldMapperFailed) {
+					t.Fatalf("joined error %v is not a custom mapper FieldError", e)
+				}
+				paths = append(paths, fe.Path)
+			}
+			if want := []string{"A", "Items[0].Name", "Items[1].Name"}; !reflect.DeepEqual(paths, want) {
+				t.Errorf("failed paths = %v, want %v", paths, want)
+			}
+		})
+	}
+}
+
+func TestMapper_OnlyFields(t *testing.T) {
+	type address struct{ City, Street string }
+	type item struct{ Name, SKU string }
+	type doc struct {
+		Name    string
+		Address address
+		Items   []item
+		Meta    map[string]string
+	}
+	type form struct {
+		Name    FormInputData
+		Address struct{ City, Street FormInputData }
+		Items   []struct{ Name, SKU FormInputData }
+		Meta    map[string]FormInputData
+	}
+
+	d := &doc{
+		Name:    "new",
+		Address: address{City: "Cairo", Street: "Nile"},
+		Items:   []item{{"n0", "s0"}, {"n1", "s1"}},
+		Meta:    map[string]string{"a": "new", "b": "new"},
+	}
+
+	f := &form{Name: FormInputData{Value: "old"}, Meta: map[string]FormInputData{"b": {Value: "old"}}}
+	err := NewMapper().MapToFormWithOptions(d, nil, f, MapOptions{
+		OnlyFields: []string{"Address.City", "Items[*].SKU", "Meta[a]"},
+	})
+	if err != nil {
+		t.Fatalf("MapToFormWithOptions() error = %v", err)
+	}
+
+	if f.Name.Value != "old" {
+		t.Errorf("Name = %q, want untouched", f.Name.Value)
+	}
+	if f.Address.City.Value != "Cairo" || f.Address.Street.Value != "" {
+		t.Errorf("Address = %+v, want only City", f.Address)
+	}
+	if len(f.Items) != 2 || f.Items[1].SKU.Value != "s1" || f.Items[1].Name.Value != "" {
+		t.Errorf("Items = %+v, want only SKUs", f.Items)
+	}
+	if f.Meta["a"].Value != "new" || f.Meta["b"].Value != "old" {
+		t.Errorf("Meta = %+v, want a refreshed and b kept", f.Meta)
+	}
+}
+
+func TestMapper_Defaults(t *testing.T) {
+	type item struct {
+		Qty int `default:"1"`
+	}
+	type order struct {
+		Country  string `default:"EG"`
+		Currency string
+		Notes    string `default:"none"`
+		Gift     bool   `default:"true"`
+		Items    []item
+	}
+	type orderForm struct {
+		Country  FormInputData
+		Currency FormInputData
+		Notes    FormInputData
+		Gift     FormInputData
+		Items    []struct{ Qty FormInputData }
+	}
+
+	mapper := NewMapper()
+	if err := mapper.ApplyConfig(&Config{Fields: map[string]FieldConfig{"Currency": {Default: "EGP"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	f := &orderForm{}
+	d := &order{Notes: "leave at door", Items: []item{{}, {Qty: 3}}}
+	if err := mapper.MapToFormWithOptions(d, nil, f, MapOptions{Defaults: map[string]string{"Country": "US"}}); err != nil {
+		t.Fatalf("MapToFormWithOptions() error = %v", err)
+	}
+
+	if f.Country.Value != "US" {
+		t.Errorf("Country = %q, want per-call default US", f.Country.Value)
+	}
+	if f.Currency.Value != "EGP" {
+		t.Errorf("Currency = %q, want configured default EGP", f.Currency.Value)
+	}
+	if f.Notes.Value != "leave at door" {
+		t.Errorf("Notes = %q, want document value", f.Notes.Value)
+	}
+	if f.Gift.Value != "false" {
+		t.Errorf("Gift = %q, want false: bools always render", f.Gift.Value)
+	}
+	if f.Items[0].Qty.Value != "1" || f.Items[1].Qty.Value != "3" {
+		t.Errorf("Items = %+v", f.Items)
+	}
+}
+
+func TestMapper_GlobalErrorsField(t *testing.T) {
+	type doc struct{ Name string }
+	type listForm struct {
+		Name       FormInputData
+		FormErrors []string
+	}
+	type inputForm struct {
+		Name       FormInputData
+		FormErrors FormInputData
+	}
+
+	valErr := &ValidationError{}
+	valErr.AddGlobal("Your session expired, please resubmit")
+	valErr.AddGlobal("Try again")
+	valErr.Add("Name", "required", "")
+
+	wantGlobals := []string{"Your session expired, please resubmit", "Try again"}
+	if got := valErr.Globals(); !reflect.DeepEqual(got, wantGlobals) {
+		t.Fatalf("Globals() = %v, want %v", got, wantGlobals)
+	}
+
+	mapper := NewMapper(WithGlobalErrorsField("FormErrors"))
+
+	lf := &listForm{}
+	if err := mapper.MapToForm(&doc{}, valErr, lf); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+	if !reflect.DeepEqual(lf.FormErrors, wantGlobals) || lf.Name.Error != "This field is required" {
+		t.Errorf("form = %+v", lf)
+	}
+
+	inf := &inputForm{}
+	if err := mapper.MapToForm(&doc{}, valErr, inf); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+	if inf.FormErrors != (FormInputData{Error: wantGlobals[0]}) {
+		t.Errorf("FormErrors = %+v", inf.FormErrors)
+	}
+
+	// Forms without the field are unaffected.
+	if err := mapper.MapToForm(&doc{}, valErr, &struct{ Name FormInputData }{}); err != nil {
+		t.Errorf("MapToForm() without the field error = %v", err)
+	}
+
+	bad := &struct{ FormErrors int }{}
+	if err := mapper.MapToForm(&doc{}, valErr, bad); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("MapToForm() with an int field error = %v, want ErrInvalidOption", err)
+	}
+}
+
+func TestMapper_StructErrorsField(t *testing.T) {
+	type address struct{ City string }
+	type doc struct {
+		Items   []TestItem
+		Address address
+	}
+	type itemForm struct {
+		ItemID    FormInputData
+		FormError string
+	}
+	type addressForm struct {
+		City      FormInputData
+		FormError []string
+	}
+	type form struct {
+		Items     []itemForm
+		Address   addressForm
+		FormError string
+	}
+
+	valErr := &ValidationError{}
+	valErr.AddMsg("Items[1]", "Duplicate line item")
+	valErr.AddMsg("Address", "Address is outside the delivery area")
+	valErr.AddMsg("Address", "Address is a PO box")
+	valErr.AddGlobal("Try again")
+
+	mapper := NewMapper(WithStructErrorsField("FormError"))
+	d := &doc{Items: []TestItem{{ItemID: "a"}, {ItemID: "a"}}}
+	f := &form{}
+	if err := mapper.MapToForm(d, valErr, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	if f.Items[0].FormError != "" || f.Items[1].FormError != "Duplicate line item" {
+		t.Errorf("Items = %+v", f.Items)
+	}
+	want := []string{"Address is outside the delivery area", "Address is a PO box"}
+	if !reflect.DeepEqual(f.Address.FormError, want) {
+		t.Errorf("Address.FormError = %v, want %v", f.Address.FormError, want)
+	}
+	// The root's errors are the global ones, left to WithGlobalErrorsField.
+	if f.FormError != "" {
+		t.Errorf("FormError = %q, want empty", f.FormError)
+	}
+
+	bad := &struct{ Address struct{ FormError int } }{}
+	if err := mapper.MapToForm(d, valErr, bad); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("MapToForm() with an int field error = %v, want ErrInvalidOption", err)
+	}
+}
@@ -2,12 +2,15 @@ module github.com/omareloui/formmap
 
 go 1.24.4
 
-require github.com/go-playground/validator/v10 v10.27.0
+require (
+	github.com/go-playground/locales v0.14.1
+	github.com/go-playground/universal-translator v0.18.1
+	github.com/go-playground/validator/v10 v10.27.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/gabriel-vasile/mimetype v1.4.8 // indirect
-	github.com/go-playground/locales v0.14.1 // indirect
-	github.com/go-playground/universal-translator v0.18.1 // indirect
 	github.com/leodido/go-urn v1.4.0 // indirect
 	golang.org/x/crypto v0.33.0 // indirect
 	golang.org/x/net v0.34.0 // indirect
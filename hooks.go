@@ -0,0 +1,92 @@
+package formmap
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// FieldHook observes or adjusts a single field mapping. Returning an error
+// stops the mapping.
+type FieldHook func(fieldPath string, docVal, formVal reflect.Value) error
+
+// BeforeField registers a hook that runs before every struct field, slice
+// element and map entry is mapped, including those handled by field mappers.
+func (m *Mapper) BeforeField(hook FieldHook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.beforeField = append(m.beforeField, hook)
+}
+
+// AfterField registers a hook that runs once a field has been mapped, so it
+// sees the final form value.
+func (m *Mapper) AfterField(hook FieldHook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.afterField = append(m.afterField, hook)
+}
+
+func (m *Mapper) fieldHooks() (before, after []FieldHook) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.beforeField, m.afterField
+}
+
+// withFieldHooks runs mapField between the registered before and after hooks.
+func (m *Mapper) withFieldHooks(path pathKey, docVal, formVal reflect.Value, mapField func() error) error {
+	before, after := m.fieldHooks()
+	if len(before) == 0 && len(after) == 0 {
+		return mapField()
+	}
+
+	fieldPath := path.String()
+
+	for _, hook := range before {
+		if err := hook(fieldPath, docVal, formVal); err != nil {
+			return &FieldError{Path: fieldPath, Kind: ErrHookFailed, Err: err}
+		}
+	}
+
+	if err := mapField(); err != nil {
+		return err
+	}
+
+	for _, hook := range after {
+		if err := hook(fieldPath, docVal, formVal); err != nil {
+			return &FieldError{Path: fieldPath, Kind: ErrHookFailed, Err: err}
+		}
+	}
+
+	return nil
+}
+
+// MapHook runs once per MapToForm call with the document, the form and the
+// validation errors being mapped. BeforeMap hooks may add errors that the
+// fields will then pick up.
+type MapHook func(doc, form any, valErr *ValidationError) error
+
+func (m *Mapper) BeforeMap(hook MapHook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.beforeMap = append(m.beforeMap, hook)
+}
+
+func (m *Mapper) AfterMap(hook MapHook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.afterMap = append(m.afterMap, hook)
+}
+
+func (m *Mapper) mapHooks() (before, after []MapHook) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.beforeMap, m.afterMap
+}
+
+func runMapHooks(hooks []MapHook, doc, form any, valErr *ValidationError) error {
+	for _, hook := range hooks {
+		if err := hook(doc, form, valErr); err != nil {
+			return fmt.Errorf("%w: %w", ErrHookFailed, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package formmap
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMapper_FieldHooks(t *testing.T) {
+	type account struct {
+		Email  string
+		Secret string
+		Tags   []string
+	}
+	type accountForm struct {
+		Email  FormInputData
+		Secret FormInputData
+		Tags   []FormInputData
+	}
+
+	mapper := NewMapper()
+	mapper.RegisterFieldMapper("Email", func(docField, formField reflect.Value, fieldPath string, valErr *ValidationError) error {
+		formField.FieldByName("Value").SetString(strings.ToLower(docField.String()))
+		return nil
+	})
+
+	var visited []string
+	mapper.BeforeField(func(fieldPath string, docVal, formVal reflect.Value) error {
+		visited = append(visited, "before "+fieldPath)
+		return nil
+	})
+	mapper.AfterField(func(fieldPath string, docVal, formVal reflect.Value) error {
+		visited = append(visited, "after "+fieldPath)
+		if fieldPath == "Secret" {
+			formVal.Addr().Interface().(*FormInputData).SetValue(strings.Repeat("*", len(docVal.String())))
+		}
+		return nil
+	})
+
+	d := &account{Email: "ADA@EXAMPLE.COM", Secret: "hunter2", Tags: []string{"a"}}
+	f := &accountForm{}
+	if err := mapper.MapToForm(d, nil, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	want := []string{
+		"before Email", "after Email",
+		"before Secret", "after Secret",
+		"before Tags", "before Tags[0]", "after Tags[0]", "after Tags",
+	}
+	if !reflect.DeepEqual(visited, want) {
+		t.Errorf("hooks ran as %v, want %v", visited, want)
+	}
+	if f.Email.Value != "ada@example.com" {
+		t.Errorf("Email = %q", f.Email.Value)
+	}
+	if f.Secret.Value != "*******" {
+		t.Errorf("Secret = %q, want masked", f.Secret.Value)
+	}
+}
+
+func TestMapper_FieldHookError(t *testing.T) {
+	type doc struct{ A, B string }
+	type form struct{ A, B FormInputData }
+
+	errStop := errors.New("stop")
+	mapper := NewMapper()
+	mapper.BeforeField(func(fieldPath string, docVal, formVal reflect.Value) error {
+		if fieldPath == "B" {
+			return errStop
+		}
+		return nil
+	})
+
+	f := &form{}
+	err := mapper.MapToForm(&doc{A: "a", B: "b"}, nil, f)
+	if !errors.Is(err, errStop) {
+		t.Fatalf("MapToForm() error = %v, want %v", err, errStop)
+	}
+	if f.A.Value != "a" || f.B.Value != "" {
+		t.Errorf("form = %+v, want A mapped and B untouched", f)
+	}
+}
+
+func TestMapper_MapHooks(t *testing.T) {
+	type signup struct {
+		Email string
+		Plan  string
+	}
+	type signupForm struct {
+		Email    FormInputData
+		Plan     FormInputData
+		Rendered bool
+	}
+
+	mapper := NewMapper()
+	mapper.BeforeMap(func(doc, form any, valErr *ValidationError) error {
+		if doc.(*signup).Plan == "" {
+			valErr.Errors["Plan"] = ValidationField{Tag: "required"}
+		}
+		return nil
+	})
+	mapper.AfterMap(func(doc, form any, valErr *ValidationError) error {
+		form.(*signupForm).Rendered = true
+		return nil
+	})
+
+	f := &signupForm{}
+	if err := mapper.MapToForm(&signup{Email: "a@b.c"}, nil, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+	if f.Plan.Error != "This field is required" {
+		t.Errorf("Plan.Error = %q, want injected error", f.Plan.Error)
+	}
+	if !f.Rendered {
+		t.Error("AfterMap hook did not run")
+	}
+
+	errStop := errors.New("stop")
+	mapper.BeforeMap(func(doc, form any, valErr *ValidationError) error { return errStop })
+
+	f = &signupForm{}
+	if err := mapper.MapToForm(&signup{Email: "a@b.c"}, nil, f); !errors.Is(err, errStop) {
+		t.Fatalf("MapToForm() error = %v, want %v", err, errStop)
+	}
+	if f.Email.Value != "" || f.Rendered {
+		t.Errorf("form = %+v, want untouched", f)
+	}
+}
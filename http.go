@@ -0,0 +1,73 @@
+package formmap
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+type FormRenderer func(w http.ResponseWriter, r *http.Request, valErr *ValidationError) error
+
+type Responder struct {
+	// Renderer re-renders the form for browser and htmx requests. When nil,
+	// every client receives JSON.
+	Renderer FormRenderer
+}
+
+// problemResponse is a problem+json body carrying the errors as
+// ValidationError encodes them.
+type problemResponse struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	jsonErrors
+}
+
+func WriteError(w http.ResponseWriter, r *http.Request, valErr *ValidationError) error {
+	return (&Responder{}).WriteError(w, r, valErr)
+}
+
+func (rs *Responder) WriteError(w http.ResponseWriter, r *http.Request, valErr *ValidationError) error {
+	if rs.Renderer != nil && wantsHTML(r) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		return rs.Renderer(w, r, valErr)
+	}
+
+	if valErr == nil {
+		valErr = &ValidationError{}
+	}
+
+	if acceptsMediaType(r, "application/problem+json") {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		return json.NewEncoder(w).Encode(problemResponse{
+			Type:       "about:blank",
+			Title:      "Validation failed",
+			Status:     http.StatusUnprocessableEntity,
+			jsonErrors: valErr.json(),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	return json.NewEncoder(w).Encode(valErr)
+}
+
+func wantsHTML(r *http.Request) bool {
+	if r.Header.Get("HX-Request") == "true" {
+		return true
+	}
+	return acceptsMediaType(r, "text/html")
+}
+
+func acceptsMediaType(r *http.Request, mediaType string) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mt, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err == nil && mt == mediaType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package formmap
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestResponder_WriteError(t *testing.T) {
+	valErr := &ValidationError{
+		Errors: Errors{"Name": ValidationField{Tag: "required"}},
+	}
+	valErr.add("Name", ValidationField{Tag: "min", Param: "3", Kind: reflect.String}, false)
+	want, err := json.Marshal(valErr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	responder := &Responder{
+		Renderer: func(w http.ResponseWriter, r *http.Request, valErr *ValidationError) error {
+			_, err := io.WriteString(w, "<form>"+valErr.MsgFor("Name")+"</form>")
+			return err
+		},
+	}
+
+	tests := []struct {
+		name        string
+		headers     map[string]string
+		contentType string
+		wantHTML    bool
+	}{
+		{"api client", map[string]string{"Accept": "application/json"}, "application/json", false},
+		{"no accept header", nil, "application/json", false},
+		{"problem json", map[string]string{"Accept": "application/problem+json"}, "application/problem+json", false},
+		{"browser", map[string]string{"Accept": "text/html,application/xhtml+xml;q=0.9"}, "text/html; charset=utf-8", true},
+		{"htmx", map[string]string{"HX-Request": "true", "Accept": "*/*"}, "text/html; charset=utf-8", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+
+			if err := responder.WriteError(w, r, valErr); err != nil {
+				t.Fatalf("WriteError() error = %v", err)
+			}
+
+			if w.Code != http.StatusUnprocessableEntity {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusUnprocessableEntity)
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.contentType)
+			}
+
+			if tt.wantHTML {
+				if got := w.Body.String(); got != "<form>This field is required</form>" {
+					t.Errorf("body = %q", got)
+				}
+				return
+			}
+
+			var body map[string]json.RawMessage
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid JSON body: %v", err)
+			}
+
+			// The errors are encoded as ValidationError encodes itself.
+			var wantBody map[string]json.RawMessage
+			if err := json.Unmarshal(want, &wantBody); err != nil {
+				t.Fatal(err)
+			}
+			for key, raw := range wantBody {
+				if string(body[key]) != string(raw) {
+					t.Errorf("body[%q] = %s, want %s", key, body[key], raw)
+				}
+			}
+			if _, ok := body["status"]; ok != (tt.contentType == "application/problem+json") {
+				t.Errorf("body = %s", w.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestWriteError_WithoutRenderer(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.Header.Set("Accept", "text/html")
+	w := httptest.NewRecorder()
+
+	if err := WriteError(w, r, nil); err != nil {
+		t.Fatalf("WriteError() error = %v", err)
+	}
+
+	if got := w.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+	if got := w.Body.String(); got != "{\"errors\":{}}\n" {
+		t.Errorf("body = %q", got)
+	}
+}
@@ -0,0 +1,149 @@
+package formmap
+
+import (
+	"reflect"
+	"time"
+)
+
+// Conversion names how a field path is mapped.
+type Conversion string
+
+const (
+	ConversionFieldMapper Conversion = "field mapper"
+	ConversionFieldConfig Conversion = "field config"
+	ConversionConditional Conversion = "conditional converter"
+	ConversionConverter   Conversion = "converter"
+	ConversionSQLNull     Conversion = "sql null"
+	ConversionText        Conversion = "text marshaler"
+	ConversionStringer    Conversion = "stringer"
+	ConversionJoinedList  Conversion = "joined list"
+	ConversionBytes       Conversion = "bytes"
+	ConversionKind        Conversion = "kind"
+	ConversionDynamic     Conversion = "dynamic"
+	ConversionStruct      Conversion = "struct"
+	ConversionList        Conversion = "list"
+	ConversionMap         Conversion = "map"
+)
+
+// FieldPath describes one path MapToForm would visit. Slice, array and map
+// elements use "[*]", so paths read like the patterns accepted by
+// RegisterFieldMapper, MapOptions and Config.
+type FieldPath struct {
+	Path       string
+	DocType    reflect.Type
+	FormType   reflect.Type
+	Conversion Conversion
+}
+
+// FieldPaths lists the field paths that resolve between docType and formType,
+// containers included, so FieldMapper and FieldConverters keys can be checked
+// at startup. Interface fields are reported as dynamic and not descended
+// into.
+func (m *Mapper) FieldPaths(docType, formType reflect.Type) []FieldPath {
+	for docType.Kind() == reflect.Ptr {
+		docType = docType.Elem()
+	}
+	for formType.Kind() == reflect.Ptr {
+		formType = formType.Elem()
+	}
+
+	var paths []FieldPath
+	if docType.Kind() == reflect.Struct && formType.Kind() == reflect.Struct {
+		m.structPaths(docType, formType, "", &paths)
+	}
+	return paths
+}
+
+func (m *Mapper) structPaths(docType, formType reflect.Type, pathPrefix string, paths *[]FieldPath) {
+	for _, plan := range m.structPlan(docType, formType) {
+		fieldPath, formFieldName, fc, skip := m.resolveTag(plan.tag, pathPrefix)
+		if skip {
+			continue
+		}
+
+		formField, found := m.planFormField(plan, formType, formFieldName)
+		if !found || !formField.IsExported() {
+			continue
+		}
+
+		m.valuePaths(docType.Field(plan.index).Type, formField.Type, fieldPath, fc, paths)
+	}
+}
+
+func (m *Mapper) valuePaths(docType, formType reflect.Type, fieldPath string, fc FieldConfig, paths *[]FieldPath) {
+	add := func(c Conversion) {
+		*paths = append(*paths, FieldPath{Path: fieldPath, DocType: docType, FormType: formType, Conversion: c})
+	}
+
+	if fieldMapper, siblingMapper := m.fieldMappersFor(pathOf(fieldPath)); fieldMapper != nil || siblingMapper != nil {
+		add(ConversionFieldMapper)
+		return
+	}
+
+	if m.isFormField(formType) {
+		add(m.leafConversion(docType, fc))
+		return
+	}
+
+	switch {
+	case docType.Kind() == reflect.Interface:
+		add(ConversionDynamic)
+	case docType.Kind() == reflect.Ptr && formType.Kind() != reflect.Ptr:
+		m.valuePaths(docType.Elem(), formType, fieldPath, fc, paths)
+	case isListKind(docType) && isListKind(formType):
+		add(ConversionList)
+		elemPath := fieldPath + "[*]"
+		m.valuePaths(docType.Elem(), formType.Elem(), elemPath, m.elemConfig(fc, pathOf(elemPath)), paths)
+	case docType.Kind() == reflect.Map && formType.Kind() == reflect.Map:
+		add(ConversionMap)
+		elemPath := fieldPath + "[*]"
+		m.valuePaths(docType.Elem(), formType.Elem(), elemPath, m.elemConfig(fc, pathOf(elemPath)), paths)
+	case docType.Kind() == reflect.Struct && formType.Kind() == reflect.Struct:
+		add(ConversionStruct)
+		m.structPaths(docType, formType, fieldPath, paths)
+	case docType.Kind() == reflect.Ptr && formType.Kind() == reflect.Ptr:
+		m.valuePaths(docType.Elem(), formType.Elem(), fieldPath, fc, paths)
+	}
+}
+
+// leafConversion mirrors the order mapFormField tries conversions in.
+func (m *Mapper) leafConversion(t reflect.Type, fc FieldConfig) Conversion {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Interface {
+		return ConversionDynamic
+	}
+
+	switch {
+	case fc.TimeLayout != "" && t == timeType,
+		fc.DurationUnit != "" && t == reflect.TypeOf(time.Duration(0)),
+		fc.Precision != nil && (t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64):
+		return ConversionFieldConfig
+	}
+
+	if m.joinsList(reflect.New(t).Elem()) {
+		return ConversionJoinedList
+	}
+
+	conditional, converter, _ := m.convertersFor(t)
+	switch {
+	case len(conditional) > 0:
+		return ConversionConditional
+	case converter != nil:
+		return ConversionConverter
+	case isSQLNull(t):
+		return ConversionSQLNull
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return ConversionText
+	case t.Implements(stringerType) || reflect.PointerTo(t).Implements(stringerType):
+		return ConversionStringer
+	case isBytes(t):
+		return ConversionBytes
+	}
+	return ConversionKind
+}
+
+func isListKind(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice || t.Kind() == reflect.Array
+}
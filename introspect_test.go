@@ -0,0 +1,67 @@
+package formmap
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMapper_FieldPaths(t *testing.T) {
+	type line struct {
+		SKU   string
+		Price float64
+	}
+	type order struct {
+		ID       testUUID
+		Placed   time.Time `formmap:"layout=2006-01-02"`
+		Tags     []string
+		Lines    []line
+		Meta     map[string]int
+		Customer *struct{ Name string }
+		Extra    any
+		Internal string `formmap:"-"`
+		Unmapped string
+	}
+	type lineForm struct {
+		SKU   FormInputData
+		Price FormInputData
+	}
+	type orderForm struct {
+		ID       FormInputData
+		Placed   FormInputData
+		Tags     FormInputData
+		Lines    []lineForm
+		Meta     map[string]FormInputData
+		Customer struct{ Name FormInputData }
+		Extra    FormInputData
+		Internal FormInputData
+	}
+
+	mapper := NewMapper()
+	mapper.RegisterFieldMapper("Lines[*].Price", func(docField, formField reflect.Value, fieldPath string, valErr *ValidationError) error {
+		return nil
+	})
+
+	got := map[string]Conversion{}
+	for _, p := range mapper.FieldPaths(reflect.TypeOf(&order{}), reflect.TypeOf(orderForm{})) {
+		got[p.Path] = p.Conversion
+	}
+
+	want := map[string]Conversion{
+		"ID":             ConversionText,
+		"Placed":         ConversionFieldConfig,
+		"Tags":           ConversionJoinedList,
+		"Lines":          ConversionList,
+		"Lines[*]":       ConversionStruct,
+		"Lines[*].SKU":   ConversionKind,
+		"Lines[*].Price": ConversionFieldMapper,
+		"Meta":           ConversionMap,
+		"Meta[*]":        ConversionConverter,
+		"Customer":       ConversionStruct,
+		"Customer.Name":  ConversionKind,
+		"Extra":          ConversionDynamic,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FieldPaths() = %v, want %v", got, want)
+	}
+}
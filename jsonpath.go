@@ -0,0 +1,150 @@
+package formmap
+
+import (
+	"reflect"
+	"strings"
+)
+
+// jsonName returns the name f has in JSON, or "" if its json tag doesn't set
+// one.
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// ToStructPaths returns a copy of v keyed by Go field paths, e.g.
+// "Settings.Theme" for "settings.theme", following the json tags of doc, a
+// document or its type. It undoes playground.WithJSONKeys for a mapper
+// without WithJSONNames. Paths that don't resolve are kept as they are.
+func (v *ValidationError) ToStructPaths(doc any) *ValidationError {
+	return v.rekey(doc, false)
+}
+
+// ToJSONPaths is the reverse of ToStructPaths.
+func (v *ValidationError) ToJSONPaths(doc any) *ValidationError {
+	return v.rekey(doc, true)
+}
+
+func (v *ValidationError) rekey(doc any, toJSON bool) *ValidationError {
+	out := &ValidationError{Errors: make(Errors)}
+	if v == nil {
+		return out
+	}
+	out.Locale = v.Locale
+
+	t := typeOf(doc)
+	for path, f := range v.Errors {
+		out.Errors[convertPath(t, path, toJSON)] = f
+	}
+	for path, all := range v.AllErrors {
+		if out.AllErrors == nil {
+			out.AllErrors = make(map[string][]ValidationField)
+		}
+		out.AllErrors[convertPath(t, path, toJSON)] = all
+	}
+	return out
+}
+
+// convertPath rewrites the field names in path between Go and json names,
+// following t. Segments below one that doesn't resolve are kept as they are.
+func convertPath(t reflect.Type, path string, toJSON bool) string {
+	var b strings.Builder
+	for _, seg := range SplitPath(path) {
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		if strings.HasPrefix(seg, "[") {
+			b.WriteString(seg)
+			if t != nil && (isListKind(t) || t.Kind() == reflect.Map) {
+				t = t.Elem()
+			} else {
+				t = nil
+			}
+			continue
+		}
+
+		name := seg
+		if f, ok := structFieldNamed(t, seg, !toJSON); ok {
+			name, t = f.Name, f.Type
+			if toJSON && jsonName(f) != "" {
+				name = jsonName(f)
+			}
+		} else {
+			t = nil
+		}
+		if b.Len() > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(name)
+	}
+	return b.String()
+}
+
+// structFieldNamed finds the field of struct type t called name, by its json
+// name first if byJSON is set.
+func structFieldNamed(t reflect.Type, name string, byJSON bool) (reflect.StructField, bool) {
+	if t == nil || t.Kind() != reflect.Struct {
+		return reflect.StructField{}, false
+	}
+	if byJSON {
+		for _, f := range reflect.VisibleFields(t) {
+			if f.IsExported() && !f.Anonymous && jsonName(f) == name {
+				return f, true
+			}
+		}
+	}
+	return t.FieldByName(name)
+}
+
+// IndexPaths lets MsgFor, HasError and the other lookups, MapToForm's
+// included, find errors by their Go field path or their json path alike,
+// e.g. "Settings.Theme" and "settings.theme", following the tags of doc, a
+// document or its type. Errors added later are indexed too. Validate indexes
+// its errors by the validated value. It returns v.
+func (v *ValidationError) IndexPaths(doc any) *ValidationError {
+	v.pathType, v.aliases = typeOf(doc), nil
+	for path := range v.Errors {
+		v.indexPath(path)
+	}
+	return v
+}
+
+func (v *ValidationError) indexPath(path string) {
+	if v.pathType == nil {
+		return
+	}
+	for _, toJSON := range []bool{true, false} {
+		alias := convertPath(v.pathType, path, toJSON)
+		if alias == path {
+			continue
+		}
+		if v.aliases == nil {
+			v.aliases = make(map[string]string)
+		}
+		v.aliases[alias] = path
+	}
+}
+
+// key returns the path an error for path is stored under.
+func (v *ValidationError) key(path string) (string, bool) {
+	if _, ok := v.Errors[path]; ok {
+		return path, true
+	}
+	if key, ok := v.aliases[path]; ok {
+		if _, ok := v.Errors[key]; ok {
+			return key, true
+		}
+	}
+	return path, false
+}
+
+func typeOf(doc any) reflect.Type {
+	if t, ok := doc.(reflect.Type); ok {
+		return t
+	}
+	return reflect.TypeOf(doc)
+}
@@ -0,0 +1,119 @@
+package formmap
+
+import (
+	"reflect"
+	"testing"
+)
+
+type jsonKeysItem struct {
+	SKU string `json:"sku"`
+}
+
+type jsonKeysDoc struct {
+	Settings struct {
+		Theme string `json:"theme"`
+	} `json:"settings"`
+	Items []jsonKeysItem `json:"items"`
+	Note  string
+}
+
+type jsonKeysForm struct {
+	Settings struct{ Theme FormInputData }
+	Items    []struct{ SKU FormInputData }
+	Note     FormInputData
+}
+
+// jsonKeysErrors returns required errors for every field of jsonKeysDoc,
+// keyed by the given paths and indexed for doc.
+func jsonKeysErrors(doc any, paths ...string) *ValidationError {
+	valErr := &ValidationError{Errors: Errors{}}
+	for _, path := range paths {
+		valErr.Errors[path] = ValidationField{Tag: "required"}
+	}
+	return valErr.IndexPaths(doc)
+}
+
+func TestValidationError_ToStructPaths(t *testing.T) {
+	doc := &jsonKeysDoc{Items: []jsonKeysItem{{}}}
+
+	valErr := jsonKeysErrors(doc, "settings.theme", "items[0].sku", "Note")
+	want := []string{"Note", "items[0].sku", "settings.theme"}
+	if got := valErr.Fields(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Fields() = %v, want %v", got, want)
+	}
+
+	structErr := valErr.ToStructPaths(doc)
+	wantStruct := []string{"Items[0].SKU", "Note", "Settings.Theme"}
+	if got := structErr.Fields(); !reflect.DeepEqual(got, wantStruct) {
+		t.Errorf("ToStructPaths().Fields() = %v, want %v", got, wantStruct)
+	}
+	if got := structErr.ToJSONPaths(reflect.TypeOf(doc)).Fields(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ToJSONPaths().Fields() = %v, want %v", got, want)
+	}
+
+	for name, tt := range map[string]struct {
+		m   *Mapper
+		err *ValidationError
+	}{
+		"struct paths": {NewMapper(), structErr},
+		"json names":   {NewMapper(WithJSONNames()), valErr},
+	} {
+		form := &jsonKeysForm{}
+		if err := tt.m.MapToForm(doc, tt.err, form); err != nil {
+			t.Fatalf("%s: MapToForm() error = %v", name, err)
+		}
+		if form.Settings.Theme.Error == "" || form.Items[0].SKU.Error == "" || form.Note.Error == "" {
+			t.Errorf("%s: form = %+v, want every error mapped", name, form)
+		}
+	}
+}
+
+func TestValidationError_IndexPaths(t *testing.T) {
+	doc := &jsonKeysDoc{Items: []jsonKeysItem{{}}}
+
+	for name, paths := range map[string][]string{
+		"go keys":   {"Settings.Theme", "Items[0].SKU", "Note"},
+		"json keys": {"settings.theme", "items[0].sku", "Note"},
+	} {
+		valErr := jsonKeysErrors(doc, paths...)
+		for _, path := range []string{"Settings.Theme", "settings.theme", "Items[0].SKU", "items[0].sku", "Note"} {
+			if !valErr.HasError(path) || valErr.MsgFor(path) != "This field is required" {
+				t.Errorf("%s: HasError/MsgFor(%q) = %v, %q", name, path, valErr.HasError(path), valErr.MsgFor(path))
+			}
+		}
+		if valErr.HasError("settings.mode") {
+			t.Errorf("%s: HasError(settings.mode) = true", name)
+		}
+
+		form := &jsonKeysForm{}
+		if err := NewMapper().MapToForm(doc, valErr, form); err != nil {
+			t.Fatalf("%s: MapToForm() error = %v", name, err)
+		}
+		if form.Settings.Theme.Error == "" || form.Items[0].SKU.Error == "" {
+			t.Errorf("%s: form = %+v, want errors found through either path", name, form)
+		}
+
+		// Later changes go through the index as well.
+		valErr.Set("settings.theme", ValidationField{Tag: "oneof", Param: "light dark"})
+		valErr.Remove("Note")
+		if got := valErr.MsgFor("Settings.Theme"); got != "Must be one of: light, dark" {
+			t.Errorf("%s: MsgFor after Set = %q", name, got)
+		}
+		if len(valErr.Errors) != 2 {
+			t.Errorf("%s: Errors = %v, want Set and Remove on the existing keys", name, valErr.Errors)
+		}
+	}
+
+	manual := (&ValidationError{Errors: Errors{"settings.theme": ValidationField{Tag: "required"}}}).IndexPaths(doc)
+	manual.Add("Note", "required", "")
+	if !manual.HasError("Settings.Theme") || !manual.HasError("Note") {
+		t.Errorf("IndexPaths() lookups failed: %v", manual.Errors)
+	}
+}
+
+func TestValidationError_AnyErrorUnder_JSONPaths(t *testing.T) {
+	valErr := jsonKeysErrors(&jsonKeysDoc{}, "settings.theme", "items[0].sku", "Note")
+	if !valErr.AnyErrorUnder("Items") || !valErr.AnyErrorUnder("settings") {
+		t.Errorf("AnyErrorUnder() missed indexed paths: %v", valErr.Errors)
+	}
+}
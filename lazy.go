@@ -0,0 +1,87 @@
+package formmap
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// LazyForm maps single document fields on demand, for templates that only
+// render a few fields of a large document. Values are converted once and
+// cached; a LazyForm is safe for concurrent use.
+type LazyForm struct {
+	m      *Mapper
+	docVal reflect.Value
+	st     *mapState
+
+	mu    sync.Mutex
+	cache map[string]FormInputData
+}
+
+// Lazy wraps doc, a pointer to a struct or a string-keyed map, for lazy
+// mapping. err is treated as in MapToForm. The document is read when fields
+// are requested, so it shouldn't change while the LazyForm is in use.
+func (m *Mapper) Lazy(doc any, err error) (*LazyForm, error) {
+	valErr, err := asValidationError(err)
+	if err != nil {
+		return nil, err
+	}
+
+	docVal := reflect.ValueOf(doc)
+	if docVal.Kind() != reflect.Ptr && docVal.Kind() != reflect.Map {
+		return nil, fmt.Errorf("%w: doc must be a pointer", ErrNotPointer)
+	}
+	if docVal.IsNil() {
+		return nil, fmt.Errorf("%w: doc cannot be nil", ErrNilInput)
+	}
+
+	st := &mapState{valErr: valErr}
+	if err := m.prepareState(st); err != nil {
+		return nil, err
+	}
+
+	return &LazyForm{m: m, docVal: docVal, st: st, cache: make(map[string]FormInputData)}, nil
+}
+
+// Field maps the document value at fieldPath, written as in error keys (e.g.
+// "Items[3].Price"), with the mapper's converters, configuration and field
+// mappers. A path that doesn't resolve, such as one through a nil pointer or
+// past the end of a slice, yields an empty value that still carries any
+// error message for the path.
+func (f *LazyForm) Field(fieldPath string) (FormInputData, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if data, ok := f.cache[fieldPath]; ok {
+		return data, nil
+	}
+
+	data, err := f.mapField(fieldPath)
+	if err != nil {
+		return FormInputData{}, err
+	}
+	f.cache[fieldPath] = data
+	return data, nil
+}
+
+func (f *LazyForm) mapField(fieldPath string) (FormInputData, error) {
+	var data FormInputData
+	docParent, docFieldVal, fc, ok := f.m.lookupDocPath(f.docVal, fieldPath)
+	if !ok {
+		setFieldErrors(&data, f.st.valErr, fieldPath)
+		return data, nil
+	}
+
+	path := pathOf(fieldPath)
+	formFieldVal := reflect.ValueOf(&data).Elem()
+	err := f.m.withFieldHooks(path, docFieldVal, formFieldVal, func() error {
+		if handled, err := f.m.applyFieldMapper(f.st, docParent, docFieldVal, formFieldVal, path); handled {
+			return err
+		}
+		if err := f.m.mapFormField(docParent, docFieldVal, formFieldVal, f.st, path, fc); err != nil {
+			return fieldError(fieldPath, ErrMappingFailed, err)
+		}
+		return nil
+	})
+	return data, err
+}
@@ -0,0 +1,105 @@
+package formmap
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestLazyForm_Field(t *testing.T) {
+	doc := &TestDocument{
+		Name:     "Widget",
+		Tags:     []string{"a", "b"},
+		Metadata: TestMetadata{Version: "1.0"},
+		Items: []TestItem{
+			{ItemID: "i1", Price: 1.5},
+			{ItemID: "i2", Price: 2.25},
+		},
+	}
+	valErr := &ValidationError{Errors: Errors{
+		"Items[1].Price": ValidationField{Tag: "required"},
+		"NestedPtr":      ValidationField{Tag: "required"},
+	}}
+
+	calls := 0
+	mapper := NewMapper()
+	mapper.RegisterFieldMapper("Items[*].ItemID", func(docField, formField reflect.Value, fieldPath string, valErr *ValidationError) error {
+		calls++
+		formField.FieldByName("Value").SetString("#" + docField.String())
+		return nil
+	})
+
+	lazy, err := mapper.Lazy(doc, valErr)
+	if err != nil {
+		t.Fatalf("Lazy() error = %v", err)
+	}
+
+	tests := []struct {
+		path, value, msg string
+	}{
+		{"Name", "Widget", ""},
+		{"Tags[1]", "b", ""},
+		{"Metadata.Version", "1.0", ""},
+		{"Items[1].Price", "2.25", "This field is required"},
+		{"Items[0].ItemID", "#i1", ""},
+		{"Items[5].Price", "", ""},
+		{"NestedPtr.Version", "", ""},
+		{"NestedPtr", "", "This field is required"},
+	}
+	for _, tt := range tests {
+		got, err := lazy.Field(tt.path)
+		if err != nil {
+			t.Fatalf("Field(%q) error = %v", tt.path, err)
+		}
+		if got.Value != tt.value || got.Error != tt.msg {
+			t.Errorf("Field(%q) = %+v, want value %q error %q", tt.path, got, tt.value, tt.msg)
+		}
+	}
+
+	// Values are cached.
+	if _, err := lazy.Field("Items[0].ItemID"); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Errorf("field mapper called %d times, want 1", calls)
+	}
+}
+
+func TestLazyForm_Errors(t *testing.T) {
+	mapper := NewMapper()
+
+	if _, err := mapper.Lazy(TestDocument{}, nil); !errors.Is(err, ErrNotPointer) {
+		t.Errorf("Lazy(non-pointer) error = %v, want ErrNotPointer", err)
+	}
+	if _, err := mapper.Lazy((*TestDocument)(nil), nil); !errors.Is(err, ErrNilInput) {
+		t.Errorf("Lazy(nil) error = %v, want ErrNilInput", err)
+	}
+
+	strict := NewMapper(WithStrictConversion())
+	lazy, err := strict.Lazy(&struct{ C chan int }{C: make(chan int)}, nil)
+	if err != nil {
+		t.Fatalf("Lazy() error = %v", err)
+	}
+	if _, err := lazy.Field("C"); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("Field() error = %v, want ErrUnsupportedType", err)
+	}
+}
+
+func TestMapper_LazyMapDocument(t *testing.T) {
+	doc := map[string]any{
+		"prices": map[string]float64{"usd": 9.5},
+		"lines":  []any{map[string]any{"sku": "A-1"}},
+	}
+
+	lazy, err := NewMapper().Lazy(doc, nil)
+	if err != nil {
+		t.Fatalf("Lazy() error = %v", err)
+	}
+
+	for path, want := range map[string]string{"prices[usd]": "9.5", "lines[0].sku": "A-1"} {
+		got, err := lazy.Field(path)
+		if err != nil || got.Value != want {
+			t.Errorf("Field(%q) = %+v, %v; want %q", path, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package formmap
+
+import "fmt"
+
+// mapLimits guards against documents that would make mapping do excessive
+// work. Zero limits are off.
+type mapLimits struct {
+	maxFields, maxLen, maxDepth int
+
+	fields, depth int
+}
+
+// enter descends into the struct, map or list at fieldPath; leave must
+// follow unless it fails.
+func (l *mapLimits) enter(fieldPath pathKey) error {
+	if l.maxDepth > 0 && l.depth >= l.maxDepth {
+		err := fmt.Errorf("%w: nested deeper than MaxDepth %d", ErrLimitExceeded, l.maxDepth)
+		if fieldPath.n == 0 {
+			return err
+		}
+		return fieldError(fieldPath.String(), ErrMappingFailed, err)
+	}
+	l.depth++
+	return nil
+}
+
+func (l *mapLimits) leave() {
+	l.depth--
+}
+
+// visit counts one field, element or map entry.
+func (l *mapLimits) visit() error {
+	l.fields++
+	if l.maxFields > 0 && l.fields > l.maxFields {
+		return fmt.Errorf("%w: more than MaxFields %d fields", ErrLimitExceeded, l.maxFields)
+	}
+	return nil
+}
+
+func (l *mapLimits) checkLen(n int) error {
+	if l.maxLen > 0 && n > l.maxLen {
+		return fmt.Errorf("%w: %d elements, more than MaxSliceLen %d", ErrLimitExceeded, n, l.maxLen)
+	}
+	return nil
+}
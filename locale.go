@@ -0,0 +1,137 @@
+package formmap
+
+import (
+	"strings"
+)
+
+// Locale describes how numbers are written for a language/region.
+type Locale struct {
+	Tag              string
+	DecimalSeparator string
+	GroupSeparator   string
+	// SymbolAfter places currency symbols after the amount ("1.234,50 €").
+	SymbolAfter bool
+}
+
+var locales = map[string]Locale{
+	"en-US": {Tag: "en-US", DecimalSeparator: ".", GroupSeparator: ","},
+	"en-GB": {Tag: "en-GB", DecimalSeparator: ".", GroupSeparator: ","},
+	"de-DE": {Tag: "de-DE", DecimalSeparator: ",", GroupSeparator: ".", SymbolAfter: true},
+	"de-CH": {Tag: "de-CH", DecimalSeparator: ".", GroupSeparator: "’"},
+	"fr-FR": {Tag: "fr-FR", DecimalSeparator: ",", GroupSeparator: " ", SymbolAfter: true},
+	"es-ES": {Tag: "es-ES", DecimalSeparator: ",", GroupSeparator: ".", SymbolAfter: true},
+	"it-IT": {Tag: "it-IT", DecimalSeparator: ",", GroupSeparator: ".", SymbolAfter: true},
+	"nl-NL": {Tag: "nl-NL", DecimalSeparator: ",", GroupSeparator: "."},
+	"pt-BR": {Tag: "pt-BR", DecimalSeparator: ",", GroupSeparator: "."},
+	"ru-RU": {Tag: "ru-RU", DecimalSeparator: ",", GroupSeparator: " ", SymbolAfter: true},
+	"sv-SE": {Tag: "sv-SE", DecimalSeparator: ",", GroupSeparator: " ", SymbolAfter: true},
+	"ja-JP": {Tag: "ja-JP", DecimalSeparator: ".", GroupSeparator: ","},
+	"zh-CN": {Tag: "zh-CN", DecimalSeparator: ".", GroupSeparator: ","},
+	"ar-EG": {Tag: "ar-EG", DecimalSeparator: ".", GroupSeparator: ","},
+}
+
+// languageDefaults picks a region when only a language is given.
+var languageDefaults = map[string]string{
+	"en": "en-US",
+	"de": "de-DE",
+	"fr": "fr-FR",
+	"es": "es-ES",
+	"it": "it-IT",
+	"nl": "nl-NL",
+	"pt": "pt-BR",
+	"ru": "ru-RU",
+	"sv": "sv-SE",
+	"ja": "ja-JP",
+	"zh": "zh-CN",
+	"ar": "ar-EG",
+}
+
+// LookupLocale resolves a BCP 47 tag such as "de-DE", "de_DE" or "de". Tags
+// with an unknown region fall back to the language's default region.
+func LookupLocale(tag string) (Locale, bool) {
+	tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+	lang, region, _ := strings.Cut(tag, "-")
+	lang = strings.ToLower(lang)
+
+	if region != "" {
+		if l, ok := locales[lang+"-"+strings.ToUpper(region)]; ok {
+			return l, true
+		}
+	}
+
+	if l, ok := locales[languageDefaults[lang]]; ok {
+		return l, true
+	}
+
+	return Locale{}, false
+}
+
+// FormatNumber rewrites a plain number such as "-1234.5" with the locale's
+// separators. Anything that isn't a plain number is returned unchanged.
+func (l Locale) FormatNumber(s string) string {
+	b, ok := appendNumber(nil, l, s)
+	if !ok {
+		return s
+	}
+	return string(b)
+}
+
+// appendNumber appends the plain number num to dst with l's separators. ok is
+// false, and dst unusable, when num isn't a plain number.
+func appendNumber[S ~string | ~[]byte](dst []byte, l Locale, num S) (_ []byte, ok bool) {
+	if len(num) > 0 && num[0] == '-' {
+		dst = append(dst, '-')
+		num = num[1:]
+	}
+
+	intEnd := 0
+	for intEnd < len(num) && num[intEnd] != '.' {
+		intEnd++
+	}
+	intPart, fracPart := num[:intEnd], num[intEnd:]
+	hasFrac := len(fracPart) > 0
+	if hasFrac {
+		fracPart = fracPart[1:]
+	}
+	if !isDigits(intPart) || (hasFrac && !isDigits(fracPart)) {
+		return dst, false
+	}
+
+	for i := 0; i < len(intPart); i++ {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			dst = append(dst, l.GroupSeparator...)
+		}
+		dst = append(dst, intPart[i])
+	}
+
+	if hasFrac {
+		dst = append(dst, l.DecimalSeparator...)
+		dst = append(dst, fracPart...)
+	}
+
+	return dst, true
+}
+
+// ParseNumber is the inverse of FormatNumber, returning a plain number that
+// strconv can parse.
+func (l Locale) ParseNumber(s string) string {
+	if l.GroupSeparator != "" {
+		s = strings.ReplaceAll(s, l.GroupSeparator, "")
+	}
+	if l.DecimalSeparator != "" && l.DecimalSeparator != "." {
+		s = strings.ReplaceAll(s, l.DecimalSeparator, ".")
+	}
+	return s
+}
+
+func isDigits[S ~string | ~[]byte](s S) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,161 @@
+package formmap
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestLookupLocale(t *testing.T) {
+	tests := []struct {
+		tag    string
+		want   string
+		wantOK bool
+	}{
+		{"de-DE", "de-DE", true},
+		{"de_de", "de-DE", true},
+		{"de-AT", "de-DE", true},
+		{"fr", "fr-FR", true},
+		{"xx-YY", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			got, ok := LookupLocale(tt.tag)
+			if ok != tt.wantOK || got.Tag != tt.want {
+				t.Errorf("LookupLocale(%q) = (%q, %v), want (%q, %v)", tt.tag, got.Tag, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestLocale_FormatNumber(t *testing.T) {
+	de, _ := LookupLocale("de-DE")
+	en, _ := LookupLocale("en-US")
+	fr, _ := LookupLocale("fr-FR")
+
+	tests := []struct {
+		name string
+		loc  Locale
+		in   string
+		want string
+	}{
+		{"de decimal", de, "1234.56", "1.234,56"},
+		{"en decimal", en, "1234.56", "1,234.56"},
+		{"fr grouping", fr, "1234567", "1\u202f234\u202f567"},
+		{"negative", de, "-1000", "-1.000"},
+		{"short", de, "999", "999"},
+		{"not a number", de, "$12.00", "$12.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.loc.FormatNumber(tt.in)
+			if got != tt.want {
+				t.Errorf("FormatNumber(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+			if tt.in[0] != '$' {
+				if back := tt.loc.ParseNumber(got); back != tt.in {
+					t.Errorf("ParseNumber(%q) = %q, want %q", got, back, tt.in)
+				}
+			}
+		})
+	}
+}
+
+func TestMapper_WithLocale(t *testing.T) {
+	mapper := NewMapper(WithLocale("de-DE"))
+
+	doc := &TestDocument{Price: 1234.56, Quantity: 12000, Items: []TestItem{{Price: 0.5}}}
+	formData := &TestFormData{}
+
+	if err := mapper.MapToForm(doc, nil, formData); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	if formData.Price.Value != "1.234,56" {
+		t.Errorf("Price = %q, want 1.234,56", formData.Price.Value)
+	}
+	// Integers stay plain unless a field opts in to grouping.
+	if formData.Quantity.Value != "12000" {
+		t.Errorf("Quantity = %q, want 12000", formData.Quantity.Value)
+	}
+	if formData.Items[0].Price.Value != "0,5" {
+		t.Errorf("Items[0].Price = %q, want 0,5", formData.Items[0].Price.Value)
+	}
+
+	back := &TestDocument{}
+	if err := mapper.MapFromForm(formData, back); err != nil {
+		t.Fatalf("MapFromForm() error = %v", err)
+	}
+	if back.Price != doc.Price || back.Quantity != doc.Quantity || back.Items[0].Price != 0.5 {
+		t.Errorf("MapFromForm() = %+v", back)
+	}
+}
+
+func TestMapper_WithLocale_Integers(t *testing.T) {
+	type doc struct {
+		Year    int
+		Count   uint32
+		Views   int64 `formmap:",group"`
+		Balance int
+	}
+	type form struct {
+		Year, Count, Views, Balance FormInputData
+	}
+
+	mapper := NewMapper(WithLocale("de-DE"))
+	if err := mapper.ApplyConfig(&Config{Fields: map[string]FieldConfig{"Balance": {Group: true}}}); err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+
+	d := &doc{Year: 2024, Count: 12000, Views: 1234567, Balance: -5000}
+	f := &form{}
+	if err := mapper.MapToForm(d, nil, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	want := form{
+		Year:    FormInputData{Value: "2024"},
+		Count:   FormInputData{Value: "12000"},
+		Views:   FormInputData{Value: "1.234.567"},
+		Balance: FormInputData{Value: "-5.000"},
+	}
+	if *f != want {
+		t.Errorf("form = %+v, want %+v", *f, want)
+	}
+
+	back := &doc{}
+	if err := mapper.MapFromForm(f, back); err != nil {
+		t.Fatalf("MapFromForm() error = %v", err)
+	}
+	if *back != *d {
+		t.Errorf("MapFromForm() = %+v, want %+v", *back, *d)
+	}
+}
+
+func TestMapper_MapToFormWithOptions_Locale(t *testing.T) {
+	mapper := NewMapper(WithLocale("de-DE"))
+
+	mapper.RegisterConverter(reflect.TypeOf(int(0)), func(v reflect.Value) string {
+		return strconv.Itoa(int(v.Int())) + " pcs"
+	})
+
+	doc := &TestDocument{Price: 1234.5, Quantity: 1500}
+
+	formData := &TestFormData{}
+	if err := mapper.MapToFormWithOptions(doc, nil, formData, MapOptions{Locale: "en-US"}); err != nil {
+		t.Fatalf("MapToFormWithOptions() error = %v", err)
+	}
+
+	if formData.Price.Value != "1,234.5" {
+		t.Errorf("Price = %q, want 1,234.5", formData.Price.Value)
+	}
+	if formData.Quantity.Value != "1500 pcs" {
+		t.Errorf("Quantity = %q, want custom converter output untouched", formData.Quantity.Value)
+	}
+
+	if err := mapper.MapToFormWithOptions(doc, nil, formData, MapOptions{Locale: "tlh"}); err == nil {
+		t.Error("expected error for unknown locale, got nil")
+	}
+}
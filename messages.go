@@ -0,0 +1,213 @@
+package formmap
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// catalogs holds message templates by locale and tag. The "" locale holds
+// the templates registered with RegisterMessage, used for every locale.
+var (
+	messagesMu sync.RWMutex
+	catalogs   = make(map[string]map[string]string)
+	msgFunc    MsgFunc
+	valueTags  map[string]bool
+)
+
+// MsgFunc computes the message for the error at path. Returning false leaves
+// it to the templates and built-in messages.
+type MsgFunc func(path string, f ValidationField) (string, bool)
+
+// RegisterMsgFunc installs fn for every message rendered for a path, e.g. by
+// MsgFor, Error and MapToForm, ahead of templates and built-in messages, so
+// copy can depend on feature flags or context. A ValidationField with a
+// Message keeps it. A nil fn removes the hook.
+func RegisterMsgFunc(fn MsgFunc) {
+	messagesMu.Lock()
+	defer messagesMu.Unlock()
+	msgFunc = fn
+}
+
+// renderMsg returns the message of the error f at path in locale.
+func renderMsg(path string, f ValidationField, locale string) string {
+	if f.Message == "" && f.Tag != "" {
+		messagesMu.RLock()
+		fn := msgFunc
+		messagesMu.RUnlock()
+		if fn != nil {
+			if msg, ok := fn(path, f); ok {
+				return msg
+			}
+		}
+	}
+	return f.MsgIn(locale)
+}
+
+// ShowValues makes the built-in messages of the given tags quote the rejected
+// value, e.g. "'purple' is not one of: red, green, blue" for oneof. oneof,
+// datetime, min, max and len have such messages; other tags and values that
+// aren't plain strings, numbers or booleans keep theirs. Values end up in
+// messages and their JSON, so leave out tags used on secrets. Calling it
+// without tags turns it off.
+func ShowValues(tags ...string) {
+	messagesMu.Lock()
+	defer messagesMu.Unlock()
+	valueTags = make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		valueTags[tag] = true
+	}
+}
+
+func showsValue(tag string) bool {
+	messagesMu.RLock()
+	defer messagesMu.RUnlock()
+	return valueTags[tag]
+}
+
+// RegisterMessage replaces the message for a validation tag in every
+// ValidationField, e.g. RegisterMessage("required", "Please fill in
+// {field}"). The template may use {param}, {field} and {value}, the rejected
+// value if the validator reported one. An empty template restores the
+// built-in message.
+func RegisterMessage(tag, template string) {
+	messagesMu.Lock()
+	defer messagesMu.Unlock()
+	setMessage("", tag, template)
+}
+
+// RegisterMessages adds templates for locale, keyed by tag, to those already
+// registered. Keys may carry a CLDR plural form of a numeric param, e.g.
+// "min.one" and "min.other", picked by the locale's plural rules; a key
+// without one matches every count. Messages rendered in locale (see
+// ValidationError.Locale and ValidationField.MsgIn) use them, falling back to
+// the base language, e.g. "ar" for "ar-EG", then to RegisterMessage and the
+// built-in English ones.
+func RegisterMessages(locale string, messages map[string]string) {
+	messagesMu.Lock()
+	defer messagesMu.Unlock()
+	for tag, template := range messages {
+		setMessage(locale, tag, template)
+	}
+}
+
+// LoadMessages reads a JSON or YAML file of templates keyed by tag, such as
+// messages.ar.json, and registers them for locale.
+func LoadMessages(locale, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading messages: %w", err)
+	}
+
+	messages := make(map[string]string)
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		err = json.Unmarshal(data, &messages)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &messages)
+	default:
+		return fmt.Errorf("unsupported messages format %q", ext)
+	}
+	if err != nil {
+		return fmt.Errorf("parsing messages %s: %w", path, err)
+	}
+
+	RegisterMessages(locale, messages)
+	return nil
+}
+
+func setMessage(locale, tag, template string) {
+	if template == "" {
+		delete(catalogs[locale], tag)
+		return
+	}
+	if catalogs[locale] == nil {
+		catalogs[locale] = make(map[string]string)
+	}
+	catalogs[locale][tag] = template
+}
+
+func lookupMessage(locale, tag, form string) (string, bool) {
+	messagesMu.RLock()
+	defer messagesMu.RUnlock()
+
+	for {
+		catalog := catalogs[locale]
+		for _, key := range []string{tag + "." + form, tag + ".other", tag} {
+			if template, ok := catalog[key]; ok {
+				return template, true
+			}
+		}
+		if locale == "" {
+			return "", false
+		}
+		if i := strings.LastIndexAny(locale, "-_"); i > 0 {
+			locale = locale[:i]
+		} else {
+			locale = ""
+		}
+	}
+}
+
+// pluralForm returns the CLDR plural category of param in locale's language:
+// "zero", "one", "two", "few", "many" or "other". Params that aren't whole
+// numbers are "other".
+func pluralForm(locale, param string) string {
+	n, err := strconv.ParseUint(param, 10, 64)
+	if err != nil {
+		return "other"
+	}
+
+	lang, _, _ := strings.Cut(strings.ToLower(locale), "-")
+	lang, _, _ = strings.Cut(lang, "_")
+	switch lang {
+	case "ja", "ko", "zh", "th", "vi", "id", "ms", "tr":
+		return "other"
+	case "fr", "pt":
+		if n <= 1 {
+			return "one"
+		}
+	case "ar":
+		switch {
+		case n == 0:
+			return "zero"
+		case n == 1:
+			return "one"
+		case n == 2:
+			return "two"
+		case n%100 >= 3 && n%100 <= 10:
+			return "few"
+		case n%100 >= 11:
+			return "many"
+		}
+	case "ru", "uk", "be", "sr", "hr", "bs":
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return "one"
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return "few"
+		default:
+			return "many"
+		}
+	case "pl":
+		switch {
+		case n == 1:
+			return "one"
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return "few"
+		default:
+			return "many"
+		}
+	default:
+		if n == 1 {
+			return "one"
+		}
+	}
+	return "other"
+}
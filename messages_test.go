@@ -0,0 +1,256 @@
+package formmap
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRegisterMessage(t *testing.T) {
+	RegisterMessage("required", "Please fill in {field}")
+	RegisterMessage("min", "At least {param} characters")
+	t.Cleanup(func() {
+		RegisterMessage("required", "")
+		RegisterMessage("min", "")
+	})
+
+	tests := []struct {
+		field ValidationField
+		want  string
+	}{
+		{ValidationField{Tag: "required", Field: "Email"}, "Please fill in Email"},
+		{ValidationField{Tag: "min", Param: "3"}, "At least 3 characters"},
+		{ValidationField{Tag: "max", Param: "5"}, "Must be at most 5 characters"},
+		{ValidationField{Tag: "required", Message: "Custom"}, "Custom"},
+	}
+	for _, tt := range tests {
+		if got := tt.field.Msg(); got != tt.want {
+			t.Errorf("%+v.Msg() = %q, want %q", tt.field, got, tt.want)
+		}
+	}
+
+	RegisterMessage("min", "")
+	if got := (ValidationField{Tag: "min", Param: "3"}).Msg(); got != "Must be at least 3 characters" {
+		t.Errorf("Msg() after reset = %q, want the built-in message", got)
+	}
+}
+
+func TestLoadMessages(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "messages.ar.json")
+	if err := os.WriteFile(jsonPath, []byte(`{"required": "هذا الحقل مطلوب", "min": "الحد الأدنى {param}"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	yamlPath := filepath.Join(dir, "messages.ar-EG.yaml")
+	if err := os.WriteFile(yamlPath, []byte("required: مطلوب\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		messagesMu.Lock()
+		defer messagesMu.Unlock()
+		delete(catalogs, "ar")
+		delete(catalogs, "ar-EG")
+	})
+
+	if err := LoadMessages("ar", jsonPath); err != nil {
+		t.Fatalf("LoadMessages(json) error = %v", err)
+	}
+	if err := LoadMessages("ar-EG", yamlPath); err != nil {
+		t.Fatalf("LoadMessages(yaml) error = %v", err)
+	}
+	if err := LoadMessages("ar", filepath.Join(dir, "messages.txt")); err == nil {
+		t.Error("LoadMessages(missing file) succeeded")
+	}
+
+	valErr := &ValidationError{Locale: "ar-EG", Errors: Errors{
+		"Name":  ValidationField{Tag: "required"},
+		"Code":  ValidationField{Tag: "min", Param: "3"},
+		"Email": ValidationField{Tag: "email"},
+	}}
+	tests := map[string]string{
+		"Name":  "مطلوب",
+		"Code":  "الحد الأدنى 3",
+		"Email": "Invalid email address",
+	}
+	for path, want := range tests {
+		if got := valErr.MsgFor(path); got != want {
+			t.Errorf("MsgFor(%q) = %q, want %q", path, got, want)
+		}
+	}
+
+	if got := valErr.Errors["Name"].MsgIn("ar"); got != "هذا الحقل مطلوب" {
+		t.Errorf("MsgIn(ar) = %q", got)
+	}
+	if got := valErr.Errors["Name"].Msg(); got != "This field is required" {
+		t.Errorf("Msg() = %q, want the built-in message", got)
+	}
+
+	form := &struct{ Name FormInputData }{}
+	if err := NewMapper().MapToForm(&struct{ Name string }{}, valErr, form); err != nil {
+		t.Fatal(err)
+	}
+	if form.Name.Error != "مطلوب" {
+		t.Errorf("form Name.Error = %q", form.Name.Error)
+	}
+}
+
+func TestRegisterMsgFunc(t *testing.T) {
+	RegisterMsgFunc(func(path string, f ValidationField) (string, bool) {
+		if f.Tag == "required" && strings.HasPrefix(path, "Billing.") {
+			return "Needed for your invoice", true
+		}
+		return "", false
+	})
+	t.Cleanup(func() { RegisterMsgFunc(nil) })
+
+	valErr := &ValidationError{Errors: Errors{
+		"Billing.Zip": ValidationField{Tag: "required"},
+		"Name":        ValidationField{Tag: "required"},
+		"Billing.VAT": ValidationField{Tag: "required", Message: "Ask your accountant"},
+	}}
+	tests := map[string]string{
+		"Billing.Zip": "Needed for your invoice",
+		"Name":        "This field is required",
+		"Billing.VAT": "Ask your accountant",
+	}
+	for path, want := range tests {
+		if got := valErr.MsgFor(path); got != want {
+			t.Errorf("MsgFor(%q) = %q, want %q", path, got, want)
+		}
+	}
+
+	type billing struct{ Zip string }
+	form := &struct{ Billing struct{ Zip FormInputData } }{}
+	if err := NewMapper().MapToForm(&struct{ Billing billing }{}, valErr, form); err != nil {
+		t.Fatal(err)
+	}
+	if form.Billing.Zip.Error != "Needed for your invoice" {
+		t.Errorf("form Billing.Zip.Error = %q", form.Billing.Zip.Error)
+	}
+}
+
+func TestPluralMessages(t *testing.T) {
+	if got := (ValidationField{Tag: "min", Param: "1"}).Msg(); got != "Must be at least 1 character" {
+		t.Errorf("min=1 Msg() = %q", got)
+	}
+	if got := (ValidationField{Tag: "len", Param: "8"}).Msg(); got != "Must be exactly 8 characters" {
+		t.Errorf("len=8 Msg() = %q", got)
+	}
+
+	RegisterMessages("ru", map[string]string{
+		"min.one":  "Минимум {param} символ",
+		"min.few":  "Минимум {param} символа",
+		"min.many": "Минимум {param} символов",
+	})
+	t.Cleanup(func() {
+		messagesMu.Lock()
+		defer messagesMu.Unlock()
+		delete(catalogs, "ru")
+	})
+
+	tests := map[string]string{
+		"1":  "Минимум 1 символ",
+		"3":  "Минимум 3 символа",
+		"5":  "Минимум 5 символов",
+		"11": "Минимум 11 символов",
+		"21": "Минимум 21 символ",
+	}
+	for param, want := range tests {
+		if got := (ValidationField{Tag: "min", Param: param}).MsgIn("ru-RU"); got != want {
+			t.Errorf("min=%s MsgIn(ru-RU) = %q, want %q", param, got, want)
+		}
+	}
+
+	forms := []struct {
+		locale, param, want string
+	}{
+		{"en", "1", "one"},
+		{"en", "0", "other"},
+		{"en", "1.5", "other"},
+		{"fr", "0", "one"},
+		{"ar", "2", "two"},
+		{"ar", "105", "few"},
+		{"ar", "11", "many"},
+		{"ja", "1", "other"},
+		{"pl", "22", "few"},
+		{"pl", "25", "many"},
+	}
+	for _, tt := range forms {
+		if got := pluralForm(tt.locale, tt.param); got != tt.want {
+			t.Errorf("pluralForm(%q, %q) = %q, want %q", tt.locale, tt.param, got, tt.want)
+		}
+	}
+}
+
+func TestValidationError_Translate(t *testing.T) {
+	RegisterMessages("de", map[string]string{"required": "Pflichtfeld"})
+	t.Cleanup(func() {
+		messagesMu.Lock()
+		defer messagesMu.Unlock()
+		delete(catalogs, "de")
+	})
+
+	valErr := &ValidationError{}
+	valErr.Add("Name", "required", "")
+	valErr.Add("Name", "min", "3")
+	valErr.AddMsg("Email", "Schon vergeben")
+
+	de := valErr.Translate("de")
+	if got := de.MsgFor("Name"); got != "Pflichtfeld" {
+		t.Errorf("MsgFor(Name) = %q, want Pflichtfeld", got)
+	}
+	if got := de.MsgsFor("Name"); len(got) != 2 || got[1] != "Must be at least 3 characters" {
+		t.Errorf("MsgsFor(Name) = %q", got)
+	}
+	if got := de.MsgFor("Email"); got != "Schon vergeben" {
+		t.Errorf("MsgFor(Email) = %q", got)
+	}
+
+	data, err := json.Marshal(de)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"message":"Pflichtfeld"`) {
+		t.Errorf("Marshal() = %s, want the translated message", data)
+	}
+
+	// The original is untouched.
+	if got := valErr.MsgFor("Name"); got != "This field is required" {
+		t.Errorf("original MsgFor(Name) = %q", got)
+	}
+}
+
+func TestShowValues(t *testing.T) {
+	ShowValues("oneof", "datetime", "max", "min")
+	t.Cleanup(func() { ShowValues() })
+
+	tests := []struct {
+		field ValidationField
+		want  string
+	}{
+		{ValidationField{Tag: "oneof", Param: "red green blue", Value: "purple", Kind: reflect.String}, "'purple' is not one of: red, green, blue"},
+		{ValidationField{Tag: "datetime", Param: "2006-01-02", Value: "2024-13-01", Kind: reflect.String}, "'2024-13-01' is not a date in the format 2006-01-02"},
+		{ValidationField{Tag: "max", Param: "3", Value: "abcd", Kind: reflect.String}, "'abcd' is longer than 3 characters"},
+		{ValidationField{Tag: "max", Param: "10", Value: 12, Kind: reflect.Int}, "12 is more than 10"},
+		// Tags left out, values that aren't scalars and missing values keep
+		// the built-in message.
+		{ValidationField{Tag: "len", Param: "2", Value: "abc", Kind: reflect.String}, "Must be exactly 2 characters"},
+		{ValidationField{Tag: "min", Param: "2", Value: []string{"a"}, Kind: reflect.Slice}, "Must be at least 2 items"},
+		{ValidationField{Tag: "oneof", Param: "a b"}, "Must be one of: a, b"},
+	}
+	for _, tt := range tests {
+		if got := tt.field.Msg(); got != tt.want {
+			t.Errorf("Msg() = %q, want %q", got, tt.want)
+		}
+	}
+
+	RegisterMessage("numeric", "'{value}' is not a valid number")
+	t.Cleanup(func() { RegisterMessage("numeric", "") })
+	f := ValidationField{Tag: "numeric", Value: "abc", Kind: reflect.String}
+	if got := f.Msg(); got != "'abc' is not a valid number" {
+		t.Errorf("Msg() = %q, want the {value} template", got)
+	}
+}
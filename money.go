@@ -0,0 +1,232 @@
+package formmap
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type Currency struct {
+	Code     string
+	Symbol   string
+	Decimals int
+}
+
+var currencies = map[string]Currency{
+	"USD": {Code: "USD", Symbol: "$", Decimals: 2},
+	"EUR": {Code: "EUR", Symbol: "€", Decimals: 2},
+	"GBP": {Code: "GBP", Symbol: "£", Decimals: 2},
+	"JPY": {Code: "JPY", Symbol: "¥", Decimals: 0},
+	"CNY": {Code: "CNY", Symbol: "CN¥", Decimals: 2},
+	"KRW": {Code: "KRW", Symbol: "₩", Decimals: 0},
+	"INR": {Code: "INR", Symbol: "₹", Decimals: 2},
+	"CAD": {Code: "CAD", Symbol: "CA$", Decimals: 2},
+	"AUD": {Code: "AUD", Symbol: "A$", Decimals: 2},
+	"CHF": {Code: "CHF", Symbol: "CHF", Decimals: 2},
+	"EGP": {Code: "EGP", Symbol: "E£", Decimals: 2},
+	"SAR": {Code: "SAR", Symbol: "SAR", Decimals: 2},
+	"AED": {Code: "AED", Symbol: "AED", Decimals: 2},
+	"KWD": {Code: "KWD", Symbol: "KWD", Decimals: 3},
+	"BHD": {Code: "BHD", Symbol: "BHD", Decimals: 3},
+	"OMR": {Code: "OMR", Symbol: "OMR", Decimals: 3},
+}
+
+// LookupCurrency returns the ISO 4217 details for code. Unknown codes fall
+// back to the code itself as the symbol with two decimals.
+func LookupCurrency(code string) (Currency, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	c, ok := currencies[code]
+	if !ok {
+		return Currency{Code: code, Symbol: code, Decimals: 2}, false
+	}
+	return c, true
+}
+
+// FormatCurrency formats amount in the currency code as CurrencyConverter
+// does, e.g. "$1,234.50".
+func FormatCurrency(amount float64, code string, opts ...CurrencyOption) string {
+	return CurrencyConverter(code, opts...)(reflect.ValueOf(amount))
+}
+
+// RegisterCurrencyMapper formats the amount at amountPath using the currency
+// code held by the sibling field currencyField of the same document struct,
+// as CurrencyConverter does. Amounts follow the mapper's WithLocale unless
+// opts set another.
+func (m *Mapper) RegisterCurrencyMapper(amountPath, currencyField string, opts ...CurrencyOption) {
+	if m.locale != "" {
+		opts = append([]CurrencyOption{WithCurrencyLocale(m.locale)}, opts...)
+	}
+
+	m.RegisterSiblingFieldMapper(amountPath, func(docParent, docField, formField reflect.Value, fieldPath string, valErr *ValidationError) error {
+		currencyVal := docParent.FieldByName(currencyField)
+		if !currencyVal.IsValid() {
+			return fmt.Errorf("currency field %s not found", currencyField)
+		}
+
+		value := CurrencyConverter(m.convertValue(currencyVal), opts...)(docField)
+
+		ff, ok := asFormField(formField)
+		if !ok {
+			return fmt.Errorf("form field %s is not a FormField", fieldPath)
+		}
+		ff.SetValue(value)
+		setFieldErrors(ff, valErr, fieldPath)
+		return nil
+	})
+}
+
+func amountOf(v reflect.Value) (float64, bool) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return 0, false
+		}
+		v = v.Elem()
+	}
+
+	if isZero(v) {
+		return 0, false
+	}
+
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	default:
+		return 0, false
+	}
+}
+
+type CurrencyOption func(*currencyFormat)
+
+type currencyFormat struct {
+	currency   Currency
+	locale     Locale
+	minorUnits bool
+}
+
+// WithMinorUnits treats stored amounts as minor units, e.g. cents for USD.
+func WithMinorUnits() CurrencyOption {
+	return func(f *currencyFormat) {
+		f.minorUnits = true
+	}
+}
+
+// WithCurrencyLocale sets the separators and symbol placement. Unknown tags
+// keep the en-US default.
+func WithCurrencyLocale(tag string) CurrencyOption {
+	return func(f *currencyFormat) {
+		if loc, ok := LookupLocale(tag); ok {
+			f.locale = loc
+		}
+	}
+}
+
+// CurrencyConverter returns a converter that formats numeric amounts in the
+// given ISO 4217 currency, e.g. "$1,234.50" or, for de-DE, "1.234,50 €".
+func CurrencyConverter(code string, opts ...CurrencyOption) ValueConverter {
+	f := &currencyFormat{}
+	f.currency, _ = LookupCurrency(code)
+	f.locale, _ = LookupLocale("en-US")
+
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	return func(v reflect.Value) string {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return ""
+			}
+			v = v.Elem()
+		}
+
+		plain, ok := f.plainAmount(v)
+		if !ok {
+			return ""
+		}
+		return f.format(plain)
+	}
+}
+
+func (f *currencyFormat) plainAmount(v reflect.Value) (string, bool) {
+	decimals := f.currency.Decimals
+
+	if f.minorUnits {
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n := v.Int()
+			// Negating in uint64 keeps math.MinInt64's magnitude.
+			abs := uint64(n)
+			if n < 0 {
+				abs = -abs
+			}
+			return minorToPlain(abs, n < 0, decimals), true
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return minorToPlain(v.Uint(), false, decimals), true
+		}
+	}
+
+	amount, ok := amountOf(v)
+	if !ok {
+		if isNumericKind(v.Kind()) {
+			amount = 0
+		} else {
+			return "", false
+		}
+	}
+
+	if f.minorUnits {
+		amount /= math.Pow10(decimals)
+	}
+	return strconv.FormatFloat(amount, 'f', decimals, 64), true
+}
+
+// minorToPlain formats abs minor units, negated if neg, without going
+// through float64.
+func minorToPlain(abs uint64, neg bool, decimals int) string {
+	sign := ""
+	if neg {
+		sign = "-"
+	}
+
+	if decimals == 0 {
+		return sign + strconv.FormatUint(abs, 10)
+	}
+
+	unit := uint64(math.Pow10(decimals))
+	frac := strconv.FormatUint(abs%unit, 10)
+	return sign + strconv.FormatUint(abs/unit, 10) + "." + strings.Repeat("0", decimals-len(frac)) + frac
+}
+
+func (f *currencyFormat) format(plain string) string {
+	sign := ""
+	if strings.HasPrefix(plain, "-") {
+		sign, plain = "-", plain[1:]
+	}
+
+	number := f.locale.FormatNumber(plain)
+	symbol := f.currency.Symbol
+
+	if f.locale.SymbolAfter {
+		return sign + number + " " + symbol
+	}
+	if symbol == f.currency.Code {
+		return sign + symbol + " " + number
+	}
+	return sign + symbol + number
+}
+
+func isNumericKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
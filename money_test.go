@@ -0,0 +1,148 @@
+package formmap
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+type TestPricedDoc struct {
+	Amount   float64
+	Currency string
+}
+
+type TestPricedForm struct {
+	Amount   FormInputData
+	Currency FormInputData
+}
+
+func TestFormatCurrency(t *testing.T) {
+	tests := []struct {
+		amount float64
+		code   string
+		want   string
+	}{
+		{1234.5, "USD", "$1,234.50"},
+		{1234.6, "jpy", "¥1,235"},
+		{0, "USD", "$0.00"},
+		{12.3456, "KWD", "KWD 12.346"},
+		{9.99, "XYZ", "XYZ 9.99"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			if got := FormatCurrency(tt.amount, tt.code); got != tt.want {
+				t.Errorf("FormatCurrency(%v, %q) = %q, want %q", tt.amount, tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapper_RegisterCurrencyMapper(t *testing.T) {
+	mapper := NewMapper()
+	mapper.RegisterCurrencyMapper("Amount", "Currency")
+
+	doc := &TestPricedDoc{Amount: 1500, Currency: "JPY"}
+	valErr := &ValidationError{Errors: Errors{"Amount": ValidationField{Tag: "lte", Param: "1000"}}}
+	form := &TestPricedForm{}
+
+	if err := mapper.MapToForm(doc, valErr, form); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	if form.Amount.Value != "¥1,500" {
+		t.Errorf("Amount.Value = %q, want %q", form.Amount.Value, "¥1,500")
+	}
+	if form.Amount.Error != "Value must be at most 1000" {
+		t.Errorf("Amount.Error = %q", form.Amount.Error)
+	}
+	if form.Currency.Value != "JPY" {
+		t.Errorf("Currency.Value = %q, want %q", form.Currency.Value, "JPY")
+	}
+}
+
+func TestMapper_RegisterCurrencyMapper_Locale(t *testing.T) {
+	// Amounts render as CurrencyConverter renders them, in the mapper's locale.
+	mapper := NewMapper(WithLocale("de-DE"))
+	mapper.RegisterCurrencyMapper("Amount", "Currency")
+
+	for _, tt := range []struct {
+		doc  TestPricedDoc
+		want string
+	}{
+		{TestPricedDoc{Amount: 1234.5, Currency: "EUR"}, "1.234,50 €"},
+		{TestPricedDoc{Amount: 0, Currency: "EUR"}, "0,00 €"},
+	} {
+		form := &TestPricedForm{}
+		if err := mapper.MapToForm(&tt.doc, nil, form); err != nil {
+			t.Fatalf("MapToForm() error = %v", err)
+		}
+		if form.Amount.Value != tt.want {
+			t.Errorf("Amount(%v).Value = %q, want %q", tt.doc.Amount, form.Amount.Value, tt.want)
+		}
+	}
+}
+
+func TestMapper_RegisterCurrencyMapper_MissingSibling(t *testing.T) {
+	mapper := NewMapper()
+	mapper.RegisterCurrencyMapper("Amount", "Unit")
+
+	err := mapper.MapToForm(&TestPricedDoc{Amount: 1}, nil, &TestPricedForm{})
+	if err == nil {
+		t.Fatal("expected error for missing currency field, got nil")
+	}
+}
+
+func TestCurrencyConverter(t *testing.T) {
+	tests := []struct {
+		name  string
+		conv  ValueConverter
+		value any
+		want  string
+	}{
+		{"usd float", CurrencyConverter("USD"), 1234.5, "$1,234.50"},
+		{"eur de-DE", CurrencyConverter("EUR", WithCurrencyLocale("de-DE")), 1234.5, "1.234,50 €"},
+		{"cents", CurrencyConverter("USD", WithMinorUnits()), int64(123450), "$1,234.50"},
+		{"negative cents", CurrencyConverter("USD", WithMinorUnits()), -5, "-$0.05"},
+		{"jpy minor units", CurrencyConverter("JPY", WithMinorUnits()), 1500, "¥1,500"},
+		{"kwd fils", CurrencyConverter("KWD", WithMinorUnits()), 12345, "KWD 12.345"},
+		{"float minor units", CurrencyConverter("USD", WithMinorUnits()), 250.0, "$2.50"},
+		{"zero", CurrencyConverter("USD"), 0.0, "$0.00"},
+		{"min int64 cents", CurrencyConverter("USD", WithMinorUnits()), int64(math.MinInt64), "-$92,233,720,368,547,758.08"},
+		{"max uint64 cents", CurrencyConverter("USD", WithMinorUnits()), uint64(math.MaxUint64), "$184,467,440,737,095,516.15"},
+		{"non numeric", CurrencyConverter("USD"), "abc", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.conv(reflect.ValueOf(tt.value)); got != tt.want {
+				t.Errorf("converter(%v) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCurrencyConverter_FieldPath(t *testing.T) {
+	type order struct {
+		TotalCents int64
+	}
+	type orderForm struct {
+		TotalCents FormInputData
+	}
+
+	mapper := NewMapper()
+	form := &orderForm{}
+	opts := MapOptions{
+		FieldConverters: map[string]ValueConverter{
+			"TotalCents": CurrencyConverter("EUR", WithMinorUnits(), WithCurrencyLocale("de")),
+		},
+	}
+
+	if err := mapper.MapToFormWithOptions(&order{TotalCents: 199}, nil, form, opts); err != nil {
+		t.Fatalf("MapToFormWithOptions() error = %v", err)
+	}
+
+	if form.TotalCents.Value != "1,99 €" {
+		t.Errorf("TotalCents = %q, want %q", form.TotalCents.Value, "1,99 €")
+	}
+}
@@ -0,0 +1,52 @@
+package formmap
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// NameTransformer normalizes a Go field name for matching.
+type NameTransformer func(name string) string
+
+// WithNameTransformer matches document fields to form fields whose names
+// transform to the same string when no form field has the exact name, e.g.
+// WithNameTransformer(SnakeCase) pairs CreatedAt with Created_At in either
+// direction. Error paths still follow the document.
+func WithNameTransformer(transform NameTransformer) MapperOption {
+	return func(m *Mapper) {
+		m.nameTransformer = transform
+	}
+}
+
+// SnakeCase converts CamelCase and Snake_Case names to lower snake_case,
+// keeping acronyms together: "UserID" and "User_ID" both become "user_id".
+func SnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 && runes[i-1] != '_' {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+func (m *Mapper) findFormField(formType reflect.Type, fieldName string) (reflect.StructField, bool) {
+	if sf, ok := formType.FieldByName(fieldName); ok || m.nameTransformer == nil {
+		return sf, ok
+	}
+
+	want := m.nameTransformer(fieldName)
+	for i := 0; i < formType.NumField(); i++ {
+		if sf := formType.Field(i); sf.IsExported() && m.nameTransformer(sf.Name) == want {
+			return sf, true
+		}
+	}
+	return reflect.StructField{}, false
+}
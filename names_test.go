@@ -0,0 +1,61 @@
+package formmap
+
+import "testing"
+
+func TestSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"CreatedAt":  "created_at",
+		"Created_At": "created_at",
+		"UserID":     "user_id",
+		"User_ID":    "user_id",
+		"HTTPServer": "http_server",
+		"Line2":      "line2",
+		"name":       "name",
+	}
+	for in, want := range tests {
+		if got := SnakeCase(in); got != want {
+			t.Errorf("SnakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMapper_WithNameTransformer(t *testing.T) {
+	type doc struct {
+		CreatedAt string
+		User_ID   string
+		Name      string
+	}
+	type form struct {
+		Created_At FormInputData
+		UserID     FormInputData
+		Name       FormInputData
+	}
+
+	mapper := NewMapper(WithNameTransformer(SnakeCase))
+	valErr := &ValidationError{Errors: Errors{"CreatedAt": ValidationField{Tag: "required"}}}
+
+	f := &form{}
+	d := &doc{CreatedAt: "today", User_ID: "42", Name: "Ada"}
+	if err := mapper.MapToForm(d, valErr, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+	if f.Created_At.Value != "today" || f.Created_At.Error != "This field is required" || f.UserID.Value != "42" || f.Name.Value != "Ada" {
+		t.Errorf("form = %+v", f)
+	}
+
+	back := &doc{}
+	if err := mapper.MapFromForm(f, back); err != nil {
+		t.Fatalf("MapFromForm() error = %v", err)
+	}
+	if *back != *d {
+		t.Errorf("MapFromForm() = %+v, want %+v", back, d)
+	}
+
+	f = &form{}
+	if err := NewMapper().MapToForm(d, nil, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+	if f.Created_At.Value != "" || f.UserID.Value != "" {
+		t.Errorf("names matched without a transformer: %+v", f)
+	}
+}
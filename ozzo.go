@@ -0,0 +1,96 @@
+package formmap
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ozzoError is the part of ozzo-validation's validation.Error that FromOzzo
+// reads, so the package doesn't depend on ozzo.
+type ozzoError interface {
+	error
+	Code() string
+	Params() map[string]any
+}
+
+// ozzoTags maps ozzo-validation error codes to the validator tags with the
+// same meaning, and the param holding their value, so the built-in messages
+// and catalogs apply.
+var ozzoTags = map[string]struct{ tag, param string }{
+	"validation_required":                        {"required", ""},
+	"validation_nil_or_not_empty_required":       {"required", ""},
+	"validation_length_too_short":                {"min", "min"},
+	"validation_length_too_long":                 {"max", "max"},
+	"validation_length_invalid":                  {"len", "min"},
+	"validation_min_greater_equal_than_required": {"gte", "threshold"},
+	"validation_min_greater_than_required":       {"gt", "threshold"},
+	"validation_max_less_equal_than_required":    {"lte", "threshold"},
+	"validation_max_less_than_required":          {"lt", "threshold"},
+	"validation_is_email":                        {"email", ""},
+	"validation_is_url":                          {"url", ""},
+	"validation_is_uuid":                         {"uuid", ""},
+}
+
+// FromOzzo converts the errors of ozzo-validation's ValidateStruct or
+// Validate, a validation.Errors map nested for structs and slices, into a
+// ValidationError keyed by paths such as "Items[0].Price". Errors with a
+// matching validator tag get it, so the built-in messages and catalogs apply;
+// others keep ozzo's message. Any other error is kept as a global error. It
+// returns nil for a nil err.
+//
+// Paths use ozzo's keys, json names by default; IndexPaths(doc) makes them
+// answer to Go paths too.
+func FromOzzo(err error) *ValidationError {
+	if err == nil {
+		return nil
+	}
+	v := &ValidationError{Errors: make(Errors)}
+	addOzzo(v, "", err)
+	return v
+}
+
+func addOzzo(v *ValidationError, path string, err error) {
+	if rv := reflect.ValueOf(err); rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String {
+		for _, key := range sortedKeys(rv) {
+			child, _ := rv.MapIndex(key).Interface().(error)
+			if child != nil {
+				addOzzo(v, ozzoPath(path, key.String()), child)
+			}
+		}
+		return
+	}
+
+	if path == "" {
+		path = GlobalPath
+	}
+	var oe ozzoError
+	if !errors.As(err, &oe) {
+		v.AddMsg(path, err.Error())
+		return
+	}
+
+	if t, ok := ozzoTags[oe.Code()]; ok {
+		var param string
+		if p, ok := oe.Params()[t.param]; ok {
+			param = fmt.Sprint(p)
+		}
+		v.add(path, ValidationField{Tag: t.tag, Param: param}, false)
+		return
+	}
+	v.add(path, ValidationField{Tag: strings.TrimPrefix(oe.Code(), "validation_"), Message: err.Error()}, false)
+}
+
+// ozzoPath appends key to path, as an index for the numeric keys ozzo uses
+// for slice elements.
+func ozzoPath(path, key string) string {
+	if _, err := strconv.Atoi(key); err == nil && path != "" {
+		return path + "[" + key + "]"
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,59 @@
+package formmap
+
+import (
+	"errors"
+	"testing"
+)
+
+// Stand-ins for ozzo-validation's validation.Errors and validation.ErrorObject.
+type fakeOzzoErrors map[string]error
+
+func (e fakeOzzoErrors) Error() string { return "validation errors" }
+
+type fakeOzzoError struct {
+	code, message string
+	params        map[string]any
+}
+
+func (e fakeOzzoError) Error() string          { return e.message }
+func (e fakeOzzoError) Code() string           { return e.code }
+func (e fakeOzzoError) Params() map[string]any { return e.params }
+
+func TestFromOzzo(t *testing.T) {
+	err := fakeOzzoErrors{
+		"name": fakeOzzoError{code: "validation_required", message: "cannot be blank"},
+		"items": fakeOzzoErrors{
+			"1": fakeOzzoErrors{
+				"sku": fakeOzzoError{code: "validation_length_too_short", message: "the length must be no less than 3", params: map[string]any{"min": 3}},
+			},
+		},
+		"code":  fakeOzzoError{code: "validation_match_invalid", message: "must be in a valid format"},
+		"email": errors.New("already registered"),
+	}
+
+	valErr := FromOzzo(err)
+	tests := map[string]string{
+		"name":         "This field is required",
+		"items[1].sku": "Must be at least 3 characters",
+		"code":         "must be in a valid format",
+		"email":        "already registered",
+	}
+	for path, want := range tests {
+		if got := valErr.MsgFor(path); got != want {
+			t.Errorf("MsgFor(%q) = %q, want %q", path, got, want)
+		}
+	}
+	if f := valErr.Errors["items[1].sku"]; f.Tag != "min" || f.Param != "3" {
+		t.Errorf("Errors[items[1].sku] = %+v, want min=3", f)
+	}
+	if f := valErr.Errors["code"]; f.Tag != "match_invalid" {
+		t.Errorf("Errors[code].Tag = %q, want match_invalid", f.Tag)
+	}
+
+	if got := FromOzzo(errors.New("boom")).Globals(); len(got) != 1 || got[0] != "boom" {
+		t.Errorf("FromOzzo(plain).Globals() = %v", got)
+	}
+	if FromOzzo(nil) != nil {
+		t.Error("FromOzzo(nil) != nil")
+	}
+}
@@ -0,0 +1,215 @@
+package formmap
+
+import (
+	"cmp"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// matchPath reports whether fieldPath matches pattern. Patterns are field
+// paths whose segments may use glob syntax: "*" matches any single field
+// name (and "Meta*" any name starting with "Meta"), "[*]" matches any slice
+// index or map key, and "**" matches any number of segments, including none.
+func matchPath(pattern, fieldPath string) bool {
+	if pattern == fieldPath {
+		return true
+	}
+	return matchSegments(SplitPath(pattern), SplitPath(fieldPath))
+}
+
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		seg := pattern[0]
+
+		if seg == "**" {
+			rest := pattern[1:]
+			for i := 0; i <= len(segments); i++ {
+				if matchSegments(rest, segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(segments) == 0 || !matchSegment(seg, segments[0]) {
+			return false
+		}
+
+		pattern = pattern[1:]
+		segments = segments[1:]
+	}
+
+	return len(segments) == 0
+}
+
+func matchSegment(pattern, segment string) bool {
+	patternIsIndex := strings.HasPrefix(pattern, "[")
+	segmentIsIndex := strings.HasPrefix(segment, "[")
+
+	if patternIsIndex != segmentIsIndex {
+		return false
+	}
+
+	if patternIsIndex {
+		return pattern == "[*]" || pattern == segment
+	}
+
+	ok, err := path.Match(pattern, segment)
+	return err == nil && ok
+}
+
+// SplitPath breaks "Items[0].Price" into "Items", "[0]" and "Price". Map keys
+// are kept whole, dots included, as in "Attrs[v1.2].Value".
+func SplitPath(fieldPath string) []string {
+	var segments []string
+
+	start := 0
+	for i := 0; i < len(fieldPath); i++ {
+		switch fieldPath[i] {
+		case '.':
+			if i > start {
+				segments = append(segments, fieldPath[start:i])
+			}
+			start = i + 1
+		case '[':
+			j := strings.IndexByte(fieldPath[i:], ']')
+			if j < 0 {
+				continue
+			}
+			if i > start {
+				segments = append(segments, fieldPath[start:i])
+			}
+			segments = append(segments, fieldPath[i:i+j+1])
+			i += j
+			start = i + 1
+		}
+	}
+	if start < len(fieldPath) {
+		segments = append(segments, fieldPath[start:])
+	}
+
+	return segments
+}
+
+func matchAnyPath(patterns []string, fieldPath string) bool {
+	for _, pattern := range patterns {
+		if matchPath(pattern, fieldPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// selectsPath reports whether fieldPath lies on the way to, at, or below a
+// path matched by one of patterns, i.e. whether mapping has to visit it. An
+// empty pattern list selects everything.
+func selectsPath(patterns []string, fieldPath string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	segments := SplitPath(fieldPath)
+	for _, pattern := range patterns {
+		patternSegs := SplitPath(pattern)
+
+		// At or below a match.
+		for i := 1; i <= len(segments); i++ {
+			if matchSegments(patternSegs, segments[:i]) {
+				return true
+			}
+		}
+
+		// An ancestor of a possible match.
+		if isAncestorPattern(patternSegs, segments) {
+			return true
+		}
+	}
+	return false
+}
+
+func isAncestorPattern(pattern, segments []string) bool {
+	for i, seg := range segments {
+		if i >= len(pattern) {
+			return false
+		}
+		if pattern[i] == "**" {
+			return true
+		}
+		if !matchSegment(pattern[i], seg) {
+			return false
+		}
+	}
+	return len(pattern) > len(segments)
+}
+
+func isPathPattern(p string) bool {
+	return strings.Contains(p, "*")
+}
+
+// lookupPath finds the entry for fieldPath, preferring an exact key and
+// otherwise the most specific matching pattern key.
+func lookupPath[V any](entries map[string]V, fieldPath string) (V, bool) {
+	if v, ok := entries[fieldPath]; ok {
+		return v, true
+	}
+
+	best, found := "", false
+	for pattern := range entries {
+		if !isPathPattern(pattern) || !matchPath(pattern, fieldPath) {
+			continue
+		}
+		if !found || moreSpecific(pattern, best) {
+			best, found = pattern, true
+		}
+	}
+
+	if !found {
+		var zero V
+		return zero, false
+	}
+	return entries[best], true
+}
+
+func moreSpecific(a, b string) bool {
+	if la, lb := literalSegments(a), literalSegments(b); la != lb {
+		return la > lb
+	}
+	if len(a) != len(b) {
+		return len(a) > len(b)
+	}
+	return a < b
+}
+
+func literalSegments(pattern string) int {
+	n := 0
+	for _, seg := range SplitPath(pattern) {
+		if !strings.Contains(seg, "*") {
+			n++
+		}
+	}
+	return n
+}
+
+// comparePaths orders field paths segment by segment, comparing numeric
+// indices as numbers.
+func comparePaths(a, b string) int {
+	as, bs := SplitPath(a), SplitPath(b)
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if c := compareSegments(as[i], bs[i]); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(as), len(bs))
+}
+
+func compareSegments(a, b string) int {
+	if strings.HasPrefix(a, "[") && strings.HasPrefix(b, "[") {
+		i, errA := strconv.Atoi(strings.Trim(a, "[]"))
+		j, errB := strconv.Atoi(strings.Trim(b, "[]"))
+		if errA == nil && errB == nil {
+			return cmp.Compare(i, j)
+		}
+	}
+	return strings.Compare(a, b)
+}
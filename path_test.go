@@ -0,0 +1,130 @@
+package formmap
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestSplitPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"Name", []string{"Name"}},
+		{"Items[0].Price", []string{"Items", "[0]", "Price"}},
+		{"Matrix[1][2]", []string{"Matrix", "[1]", "[2]"}},
+		{"Attrs[color].Value", []string{"Attrs", "[color]", "Value"}},
+		{"Attrs[v1.2].Value", []string{"Attrs", "[v1.2]", "Value"}},
+		{"Tags[a.b]", []string{"Tags", "[a.b]"}},
+		{"Items[0", []string{"Items[0"}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := SplitPath(tt.path); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitPath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"Name", "Name", true},
+		{"Name", "Names", false},
+		{"Items[*].Price", "Items[3].Price", true},
+		{"Items[*].Price", "Items[3].ItemID", false},
+		{"Items[*]", "Items[3].Price", false},
+		{"Items[1].Price", "Items[0].Price", false},
+		{"Metadata.*", "Metadata.Version", true},
+		{"Metadata.*", "Metadata", false},
+		{"Meta*.Version", "Metadata.Version", true},
+		{"*", "Items[0]", false},
+		{"**.Price", "Price", true},
+		{"**.Price", "Items[0].Price", true},
+		{"**.Price", "Orders[0].Items[2].Price", true},
+		{"Orders.**", "Orders[1].Items[0].Price", true},
+		{"Orders.**", "Customer.Name", false},
+		{"Attrs[*]", "Attrs[color]", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"~"+tt.path, func(t *testing.T) {
+			if got := matchPath(tt.pattern, tt.path); got != tt.want {
+				t.Errorf("matchPath(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectsPath(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		path     string
+		want     bool
+	}{
+		{nil, "Anything", true},
+		{[]string{"Address.City"}, "Address", true},
+		{[]string{"Address.City"}, "Address.City", true},
+		{[]string{"Address"}, "Address.City", true},
+		{[]string{"Address.City"}, "Address.Street", false},
+		{[]string{"Address.City"}, "Name", false},
+		{[]string{"Items[*].Price"}, "Items", true},
+		{[]string{"Items[*].Price"}, "Items[3]", true},
+		{[]string{"Items[*].Price"}, "Items[3].Price", true},
+		{[]string{"Items[*].Price"}, "Items[3].Name", false},
+		{[]string{"Items[1]"}, "Items[0]", false},
+		{[]string{"**.Email"}, "Contacts[0]", true},
+		{[]string{"Name", "Email"}, "Email", true},
+	}
+
+	for _, tt := range tests {
+		if got := selectsPath(tt.patterns, tt.path); got != tt.want {
+			t.Errorf("selectsPath(%v, %q) = %v, want %v", tt.patterns, tt.path, got, tt.want)
+		}
+	}
+}
+
+type testKeyColor int
+
+func (c testKeyColor) String() string { return [...]string{"red", "green"}[c] }
+
+func TestPathKey(t *testing.T) {
+	var buf []byte
+	root := rootPath(&buf)
+
+	items := root.field("Items")
+	first := items.index(0).field("Price")
+	if got := first.String(); got != "Items[0].Price" {
+		t.Errorf("path = %q, want Items[0].Price", got)
+	}
+
+	// A sibling reuses the buffer past its parent without touching it.
+	second := items.index(12).field("SKU")
+	if got := second.String(); got != "Items[12].SKU" {
+		t.Errorf("path = %q, want Items[12].SKU", got)
+	}
+	if got := items.String(); got != "Items" {
+		t.Errorf("parent path = %q, want Items", got)
+	}
+
+	if got, ok := lookupPathKey(map[string]int{"Items[*].SKU": 1}, second); !ok || got != 1 {
+		t.Errorf("lookupPathKey() = %d, %v; want pattern match", got, ok)
+	}
+	if _, ok := lookupPathKey(map[string]int{"Name": 1}, second); ok {
+		t.Error("lookupPathKey() matched an unrelated path")
+	}
+
+	for _, key := range []any{"usd", -3, uint8(7), true, 1.5, testKeyColor(1)} {
+		want := fmt.Sprintf("Prices[%v]", key)
+		if got := root.field("Prices").key(reflect.ValueOf(key)).String(); got != want {
+			t.Errorf("key path = %q, want %q", got, want)
+		}
+	}
+}
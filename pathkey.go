@@ -0,0 +1,101 @@
+package formmap
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// pathKey is a field path under construction. Mapping walks documents depth
+// first, so all paths of one call share a buffer: a child writes past its
+// parent's end, over whatever a finished sibling left there. The path is only
+// turned into a string when something keeps it, such as an error, a hook or
+// a report; map lookups use the bytes directly.
+type pathKey struct {
+	buf *[]byte
+	n   int
+}
+
+// rootPath starts a path in buf, which is reused across calls.
+func rootPath(buf *[]byte) pathKey {
+	if *buf == nil {
+		*buf = make([]byte, 0, 64)
+	}
+	*buf = (*buf)[:0]
+	return pathKey{buf: buf}
+}
+
+// pathOf wraps an existing path string, for callers outside the mapping hot
+// path.
+func pathOf(s string) pathKey {
+	buf := []byte(s)
+	return pathKey{buf: &buf, n: len(buf)}
+}
+
+func (p pathKey) bytes() []byte {
+	if p.buf == nil {
+		return nil
+	}
+	return (*p.buf)[:p.n]
+}
+
+func (p pathKey) String() string {
+	return string(p.bytes())
+}
+
+// field appends ".name", or just name at the root.
+func (p pathKey) field(name string) pathKey {
+	b := (*p.buf)[:p.n]
+	if p.n > 0 {
+		b = append(b, '.')
+	}
+	b = append(b, name...)
+	return p.set(b)
+}
+
+// index appends "[i]".
+func (p pathKey) index(i int) pathKey {
+	b := append((*p.buf)[:p.n], '[')
+	b = strconv.AppendInt(b, int64(i), 10)
+	b = append(b, ']')
+	return p.set(b)
+}
+
+// key appends a map key the way fmt's %v prints it.
+func (p pathKey) key(k reflect.Value) pathKey {
+	b := append((*p.buf)[:p.n], '[')
+	switch {
+	case k.Type().NumMethod() > 0:
+		b = fmt.Append(b, k.Interface())
+	case k.Kind() == reflect.String:
+		b = append(b, k.String()...)
+	case k.CanInt():
+		b = strconv.AppendInt(b, k.Int(), 10)
+	case k.CanUint():
+		b = strconv.AppendUint(b, k.Uint(), 10)
+	default:
+		b = fmt.Append(b, k.Interface())
+	}
+	b = append(b, ']')
+	return p.set(b)
+}
+
+func (p pathKey) set(b []byte) pathKey {
+	*p.buf = b
+	return pathKey{buf: p.buf, n: len(b)}
+}
+
+// lookupPathKey is lookupPath for a pathKey. Exact matches don't need the
+// path as a string.
+func lookupPathKey[V any](entries map[string]V, p pathKey) (V, bool) {
+	if v, ok := entries[string(p.bytes())]; ok {
+		return v, true
+	}
+	for pattern := range entries {
+		if isPathPattern(pattern) {
+			return lookupPath(entries, p.String())
+		}
+	}
+	var zero V
+	return zero, false
+}
@@ -0,0 +1,125 @@
+package formmap
+
+import (
+	"reflect"
+)
+
+// fieldTag is what a document field's tags say about it, before any
+// path-based configuration is applied.
+type fieldTag struct {
+	name     string
+	formName string
+	fc       FieldConfig
+	skip     bool
+}
+
+// parseFieldTag reads the `json` (with WithJSONNames), `formmap` and `default`
+// tags of docField.
+func (m *Mapper) parseFieldTag(docField reflect.StructField) fieldTag {
+	ft := fieldTag{name: m.getFieldName(docField), formName: docField.Name}
+	if ft.name == "-" {
+		ft.skip = true
+		return ft
+	}
+
+	ft.fc.Default = docField.Tag.Get("default")
+
+	if tag := docField.Tag.Get("formmap"); tag != "" {
+		name, tagFC := parseFormmapTag(tag)
+		ft.fc = ft.fc.merge(tagFC)
+		switch name {
+		case "-":
+			ft.skip = true
+		case "":
+		default:
+			ft.formName = name
+		}
+	}
+	return ft
+}
+
+// resolveTag applies the configuration for the field's path to what its tags
+// say. Fields skipped by a `json:"-"` tag have no path.
+func (m *Mapper) resolveTag(ft fieldTag, pathPrefix string) (fieldPath, formFieldName string, fc FieldConfig, skip bool) {
+	path, formFieldName, fc, skip := m.resolvePath(ft, pathOf(pathPrefix))
+	return path.String(), formFieldName, fc, skip
+}
+
+// resolvePath is resolveTag for a path under construction.
+func (m *Mapper) resolvePath(ft fieldTag, parent pathKey) (fieldPath pathKey, formFieldName string, fc FieldConfig, skip bool) {
+	if ft.name == "-" {
+		return pathKey{}, "", fc, true
+	}
+
+	fieldPath = parent.field(ft.name)
+	if ft.skip {
+		return fieldPath, "", ft.fc, true
+	}
+
+	fc = ft.fc
+	if configured, ok := m.fieldConfig(fieldPath); ok {
+		fc = fc.merge(configured)
+	}
+
+	if fc.Skip {
+		return fieldPath, "", fc, true
+	}
+
+	formFieldName = ft.formName
+	if fc.Name != "" {
+		formFieldName = fc.Name
+	}
+
+	return fieldPath, formFieldName, fc, false
+}
+
+// fieldPlan caches the type-level facts about one exported document field and
+// the form field its tags point to. Everything that depends on the field path
+// is still resolved per call, since configuration may target single paths.
+type fieldPlan struct {
+	index     int
+	tag       fieldTag
+	formField reflect.StructField
+	found     bool
+}
+
+type planKey struct {
+	doc, form reflect.Type
+}
+
+// structPlan returns the cached field plans for mapping between docType and
+// formType. Plans only depend on the types and on options fixed at
+// construction, so they never go stale. A nil formType plans the document
+// fields alone.
+func (m *Mapper) structPlan(docType, formType reflect.Type) []fieldPlan {
+	key := planKey{docType, formType}
+	if plans, ok := m.plans.Load(key); ok {
+		return plans.([]fieldPlan)
+	}
+
+	plans := make([]fieldPlan, 0, docType.NumField())
+	for i := 0; i < docType.NumField(); i++ {
+		docField := docType.Field(i)
+		if !docField.IsExported() {
+			continue
+		}
+
+		p := fieldPlan{index: i, tag: m.parseFieldTag(docField)}
+		if !p.tag.skip && formType != nil {
+			p.formField, p.found = m.findFormField(formType, p.tag.formName)
+		}
+		plans = append(plans, p)
+	}
+
+	m.plans.Store(key, plans)
+	return plans
+}
+
+// planFormField returns the form field for formFieldName, using the cached
+// lookup unless configuration renamed the field.
+func (m *Mapper) planFormField(p fieldPlan, formType reflect.Type, formFieldName string) (reflect.StructField, bool) {
+	if formFieldName == p.tag.formName {
+		return p.formField, p.found
+	}
+	return m.findFormField(formType, formFieldName)
+}
@@ -0,0 +1,52 @@
+package formmap
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMapper_StructPlanCache(t *testing.T) {
+	type doc struct {
+		Name     string
+		Nickname string `formmap:"Alias"`
+		secret   string
+	}
+	type form struct {
+		Name  FormInputData
+		Alias FormInputData
+		Other FormInputData
+	}
+
+	mapper := NewMapper()
+	d := &doc{Name: "Ada", Nickname: "ada", secret: "x"}
+
+	f := &form{}
+	if err := mapper.MapToForm(d, nil, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+	if f.Name.Value != "Ada" || f.Alias.Value != "ada" {
+		t.Errorf("form = %+v", f)
+	}
+
+	plans, ok := mapper.plans.Load(planKey{reflect.TypeOf(doc{}), reflect.TypeOf(form{})})
+	if !ok || len(plans.([]fieldPlan)) != 2 {
+		t.Fatalf("cached plans = %v, want two exported fields", plans)
+	}
+
+	// Configuration applied after the plan was cached still takes effect.
+	err := mapper.ApplyConfig(&Config{Fields: map[string]FieldConfig{
+		"Name":     {Name: "Other"},
+		"Nickname": {Skip: true},
+	}})
+	if err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+
+	f = &form{}
+	if err := mapper.MapToForm(d, nil, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+	if f.Other.Value != "Ada" || f.Name.Value != "" || f.Alias.Value != "" {
+		t.Errorf("form after config = %+v", f)
+	}
+}
@@ -0,0 +1,102 @@
+package playground
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/omareloui/formmap"
+)
+
+// crossFieldTags are the validator tags whose Param names another field.
+var crossFieldTags = map[string]bool{
+	"eqfield": true, "nefield": true, "gtfield": true, "gtefield": true, "ltfield": true, "ltefield": true,
+	"eqcsfield": true, "necsfield": true, "gtcsfield": true, "gtecsfield": true, "ltcsfield": true, "ltecsfield": true,
+	"fieldcontains": true, "fieldexcludes": true,
+}
+
+// paramLabel names the field the Param of fe, a Go field name such as
+// "Password", refers to: its label from WithLabels, or else its name in error
+// keys. fe failed at path while validating a value of type t, whose name root
+// leads fe's namespace. It returns "" for errors of other tags, Params that
+// don't resolve and labels that are Param itself.
+func (v *Validator) paramLabel(t reflect.Type, root string, fe validator.FieldError, path string) string {
+	if t == nil || !crossFieldTags[fe.Tag()] || fe.Param() == "" {
+		return ""
+	}
+
+	// Field tags name a sibling of the failing field, cross-struct tags a
+	// field of the validated value.
+	prefix := ""
+	if !strings.Contains(fe.Tag(), "csfield") {
+		ns := fe.StructNamespace()
+		if root != "" {
+			ns = strings.TrimPrefix(ns, root+".")
+		}
+		segs := formmap.SplitPath(ns)
+		if len(segs) == 0 {
+			return ""
+		}
+		for _, seg := range segs[:len(segs)-1] {
+			if t = elemType(t, seg); t == nil {
+				return ""
+			}
+		}
+		if i := strings.LastIndexByte(path, '.'); i >= 0 {
+			prefix = path[:i]
+		}
+	}
+
+	name := ""
+	for _, goName := range strings.Split(fe.Param(), ".") {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return ""
+		}
+		sf, ok := t.FieldByName(goName)
+		if !ok {
+			return ""
+		}
+		name, t = v.keyName(sf), sf.Type
+		if prefix != "" {
+			prefix += "."
+		}
+		prefix += name
+	}
+
+	label := name
+	if v.labels != nil {
+		if l := v.labels(prefix); l != "" {
+			label = l
+		}
+	}
+	if label == fe.Param() {
+		return ""
+	}
+	return label
+}
+
+// elemType returns the type seg, a field name or an index such as "[0]",
+// leads to from t, or nil if it doesn't resolve.
+func elemType(t reflect.Type, seg string) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if strings.HasPrefix(seg, "[") {
+		if k := t.Kind(); k == reflect.Slice || k == reflect.Array || k == reflect.Map {
+			return t.Elem()
+		}
+		return nil
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	sf, ok := t.FieldByName(seg)
+	if !ok {
+		return nil
+	}
+	return sf.Type
+}
@@ -0,0 +1,99 @@
+package playground
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/omareloui/formmap"
+)
+
+func TestValidator_CrossFieldParamLabels(t *testing.T) {
+	type rangeDoc struct {
+		Min int `json:"min"`
+		Max int `json:"max" validate:"gtfield=Min"`
+	}
+	type doc struct {
+		Password    string     `json:"password"`
+		ConfirmPass string     `json:"confirm_password" validate:"eqfield=Password"`
+		Start       int        `json:"start"`
+		Ranges      []rangeDoc `json:"ranges" validate:"dive"`
+		Window      struct {
+			End int `json:"end" validate:"gtecsfield=Start"`
+		} `json:"window"`
+	}
+	type rangeForm struct{ Min, Max formmap.FormInputData }
+	type form struct {
+		ConfirmPass formmap.FormInputData
+		Ranges      []rangeForm
+		Window      struct{ End formmap.FormInputData }
+	}
+
+	d := &doc{Password: "secret", ConfirmPass: "other", Start: 5, Ranges: []rangeDoc{{Min: 5, Max: 1}}}
+	d.Window.End = 1
+
+	mapper := formmap.NewMapper()
+	if err := mapper.ApplyConfig(&formmap.Config{Fields: map[string]formmap.FieldConfig{
+		"Password":      {Label: "Your password"},
+		"Ranges[*].Min": {Label: "Minimum"},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	valErr := New(WithLabels(mapper.Label)).Validate(d)
+	want := map[string]string{
+		"ConfirmPass":   "This field must match Your password",
+		"Ranges[0].Max": "Must be greater than Minimum",
+		"Window.End":    "Must be at least Start",
+	}
+
+	f := &form{}
+	if err := mapper.MapToForm(d, valErr, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+	mapped := map[string]string{
+		"ConfirmPass":   f.ConfirmPass.Error,
+		"Ranges[0].Max": f.Ranges[0].Max.Error,
+		"Window.End":    f.Window.End.Error,
+	}
+
+	data, err := json.Marshal(valErr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded formmap.ValidationError
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every renderer shows the labels.
+	for path, msg := range want {
+		if got := mapped[path]; got != msg {
+			t.Errorf("MapToForm %s error = %q, want %q", path, got, msg)
+		}
+		if got := valErr.MsgFor(path); got != msg {
+			t.Errorf("MsgFor(%q) = %q, want %q", path, got, msg)
+		}
+		if !strings.Contains(valErr.Error(), path+": "+msg) {
+			t.Errorf("Error() = %q, missing %q", valErr.Error(), msg)
+		}
+		if got := decoded.MsgFor(path); got != msg {
+			t.Errorf("JSON round trip MsgFor(%q) = %q, want %q", path, got, msg)
+		}
+	}
+	if got := valErr.Errors["ConfirmPass"].Param; got != "Password" {
+		t.Errorf("Param = %q, want Password", got)
+	}
+
+	// Without labels, the other field is named as in error keys.
+	jsonErr := New(WithJSONKeys()).Validate(d)
+	for path, msg := range map[string]string{
+		"confirm_password": "This field must match password",
+		"ranges[0].max":    "Must be greater than min",
+		"window.end":       "Must be at least start",
+	} {
+		if got := jsonErr.MsgFor(path); got != msg {
+			t.Errorf("WithJSONKeys MsgFor(%q) = %q, want %q", path, got, msg)
+		}
+	}
+}
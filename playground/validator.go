@@ -0,0 +1,318 @@
+// Package playground validates documents with go-playground/validator and
+// reports failures as formmap errors, keyed by the paths MapToForm uses.
+package playground
+
+import (
+	"cmp"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/omareloui/formmap"
+)
+
+type Validator struct {
+	validator     *validator.Validate
+	trans         ut.Translator
+	errorHandlers []ErrorHandler
+	catchAllKey   string
+	catchAllMsg   string
+	nameTag       string
+	labels        func(path string) string
+}
+
+type Option func(*Validator)
+
+var _ formmap.Validator = (*Validator)(nil)
+
+// ErrorHandler turns an error ParseError gets that doesn't come from the
+// validator, such as a *json.SyntaxError or a domain error, into field or
+// global errors. Returning nil passes it on.
+type ErrorHandler func(err error) *formmap.ValidationError
+
+// WithErrorHandler adds fn to the handlers ParseError tries, in order, on
+// errors that don't come from the validator. Errors no handler takes become
+// a global "invalid" error, see WithCatchAllError.
+func WithErrorHandler(fn ErrorHandler) Option {
+	return func(v *Validator) {
+		v.errorHandlers = append(v.errorHandlers, fn)
+	}
+}
+
+// WithJSONKeys keys errors by json names, e.g. "settings.theme" rather than
+// "Settings.Theme", for JavaScript clients. Fields without a json name keep
+// their Go name. Map them with a mapper using formmap.WithJSONNames, or
+// convert them back with ToStructPaths.
+func WithJSONKeys() Option {
+	return WithFieldNameTag("json")
+}
+
+// WithTagName reads validation rules from the given struct tag instead of
+// `validate`, e.g. `form:"required,email"`.
+func WithTagName(name string) Option {
+	return func(v *Validator) {
+		v.validator.SetTagName(name)
+	}
+}
+
+// WithFieldNameTag names fields in error keys after the given struct tag,
+// e.g. "form" or "json", falling back to the Go name where the tag is
+// missing or "-".
+func WithFieldNameTag(tag string) Option {
+	return func(v *Validator) {
+		v.nameTag = tag
+		v.validator.RegisterTagNameFunc(func(f reflect.StructField) string {
+			name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+	}
+}
+
+// keyName returns the name f has in error keys.
+func (v *Validator) keyName(f reflect.StructField) string {
+	if v.nameTag == "" {
+		return f.Name
+	}
+	name, _, _ := strings.Cut(f.Tag.Get(v.nameTag), ",")
+	if name == "" || name == "-" {
+		return f.Name
+	}
+	return name
+}
+
+// WithLabels names the other field of cross-field errors such as
+// eqfield=Password by the label fn returns for its error key, e.g. a mapper's
+// Label, so messages read "This field must match Your password". Fields fn
+// has no label for keep their key name.
+func WithLabels(fn func(path string) string) Option {
+	return func(v *Validator) {
+		v.labels = fn
+	}
+}
+
+// WithCatchAllError sets the key and message of the "invalid" error ParseError
+// returns for errors no handler takes, e.g. "form" and "We couldn't read your
+// submission". An empty key keeps "_error", where Globals and
+// WithGlobalErrorsField find it; an empty message keeps the built-in one.
+func WithCatchAllError(key, message string) Option {
+	return func(v *Validator) {
+		v.catchAllKey, v.catchAllMsg = key, message
+	}
+}
+
+// New returns a Validator with required structs enabled, configured by opts.
+func New(opts ...Option) *Validator {
+	val := validator.New(validator.WithRequiredStructEnabled())
+
+	v := &Validator{validator: val}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+func (v *Validator) Validate(input any) *formmap.ValidationError {
+	return v.ParseErrorFor(v.validator.Struct(input), input)
+}
+
+// ParseError converts validator errors into a ValidationError, dropping the
+// root struct name that leads each namespace. The root is read from the
+// error's struct namespace, so a json or form field name that happens to match
+// it is kept; errors of anonymous roots, which have none, are better parsed
+// with ParseErrorFor.
+func (v *Validator) ParseError(err error) *formmap.ValidationError {
+	return v.parseError(err, nil, func(fe validator.FieldError) string {
+		root, _, ok := strings.Cut(fe.StructNamespace(), ".")
+		if !ok {
+			return ""
+		}
+		return root
+	})
+}
+
+// ParseErrorFor converts the errors of validating root, the value passed to
+// the validator, into a ValidationError. Exactly the name of root's type is
+// dropped from each namespace, and nothing for anonymous structs or a nil
+// root, whose namespaces start at the field. Paths are indexed for root as
+// Validate does.
+func (v *Validator) ParseErrorFor(err error, root any) *formmap.ValidationError {
+	t, ok := root.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(root)
+	}
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	name := ""
+	if t != nil {
+		name = t.Name()
+	}
+
+	valErr := v.parseError(err, t, func(validator.FieldError) string { return name })
+	if valErr != nil {
+		valErr.IndexPaths(t)
+	}
+	return valErr
+}
+
+// parseError converts err, dropping the name rootName returns for each field
+// error from the head of its namespace. Cross-field params are labelled
+// following t, the validated type, if known.
+func (v *Validator) parseError(err error, t reflect.Type, rootName func(validator.FieldError) string) *formmap.ValidationError {
+	if err == nil {
+		return nil
+	}
+
+	valErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		for _, handle := range v.errorHandlers {
+			if valErr := handle(err); valErr != nil {
+				return valErr
+			}
+		}
+		key := cmp.Or(v.catchAllKey, formmap.GlobalPath)
+		return &formmap.ValidationError{
+			Errors: formmap.Errors{
+				key: formmap.ValidationField{
+					Tag:     "invalid",
+					Field:   key,
+					Message: v.catchAllMsg,
+				},
+			},
+		}
+	}
+
+	valerr := &formmap.ValidationError{Errors: formmap.Errors{}}
+	if v.trans != nil {
+		// Tags the translator lacks then render from the same locale's catalog.
+		valerr.Locale = v.trans.Locale()
+	}
+	for _, err := range valErrors {
+		path := err.Namespace()
+		root := rootName(err)
+		if root != "" {
+			if rest, ok := strings.CutPrefix(path, root+"."); ok {
+				path = rest
+			}
+		}
+		// Struct-level errors reported without a field name end in a dot
+		// and belong to the struct itself, or the whole form at the root.
+		path = strings.TrimSuffix(path, ".")
+		if path == "" {
+			path = formmap.GlobalPath
+		}
+
+		valerr.AddField(path, formmap.ValidationField{
+			Tag:        err.ActualTag(),
+			Param:      err.Param(),
+			ParamLabel: v.paramLabel(t, root, err, path),
+			Field:      err.Field(),
+			Message:    v.translate(err),
+			Value:      err.Value(),
+			Kind:       err.Kind(),
+		})
+	}
+
+	return valerr
+}
+
+// RegisterTranslator makes errors carry messages from trans, e.g. a
+// universal-translator locale, instead of the built-in English ones. register
+// installs the translations, as the validator's translations packages do:
+//
+//	v.RegisterTranslator(trans, de_translations.RegisterDefaultTranslations)
+//
+// Translated messages are stored on ValidationField.Message, which Msg prefers.
+// Errors take the translator's locale, so tags without a translation fall back
+// to messages registered for it with formmap.RegisterMessages, then the
+// built-in one.
+func (v *Validator) RegisterTranslator(trans ut.Translator, register func(*validator.Validate, ut.Translator) error) error {
+	if register != nil {
+		if err := register(v.validator, trans); err != nil {
+			return err
+		}
+	}
+	v.trans = trans
+	return nil
+}
+
+// translate returns the translated message of err, or "" without one.
+func (v *Validator) translate(err validator.FieldError) string {
+	if v.trans == nil {
+		return ""
+	}
+	// Translate falls back to the error text when the tag has no translation.
+	if msg := err.Translate(v.trans); msg != err.Error() {
+		return msg
+	}
+	return ""
+}
+
+// ValidateMap validates a map document, such as a CMS field set, against
+// rules keyed like it, with nested maps for nested documents, e.g.
+//
+//	v.ValidateMap(doc, map[string]any{"title": "required", "seo": map[string]any{"slug": "required,max=60"}})
+//
+// Errors are keyed by the paths MapToForm uses for map documents, such as
+// "seo.slug" or "tags[1]". It returns nil when doc is valid. Messages are not
+// translated, as map keys have no field names to put in them.
+func (v *Validator) ValidateMap(doc, rules map[string]any) *formmap.ValidationError {
+	valErr := &formmap.ValidationError{Errors: formmap.Errors{}}
+	addMapErrors(valErr, "", v.validator.ValidateMap(doc, rules))
+	if valErr.IsEmpty() {
+		return nil
+	}
+	return valErr
+}
+
+func addMapErrors(valErr *formmap.ValidationError, prefix string, errs map[string]any) {
+	for _, key := range slices.Sorted(maps.Keys(errs)) {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		switch err := errs[key].(type) {
+		case map[string]any:
+			addMapErrors(valErr, path, err)
+		case validator.ValidationErrors:
+			for _, fe := range err {
+				elemPath := path
+				// Errors under dive are namespaced by their index alone.
+				if ns := fe.Namespace(); strings.HasPrefix(ns, "[") {
+					elemPath += ns
+				}
+				valErr.AddField(elemPath, formmap.ValidationField{Tag: fe.ActualTag(), Param: fe.Param(), Field: key, Value: fe.Value(), Kind: fe.Kind()})
+			}
+		case error:
+			valErr.AddMsg(path, err.Error())
+		}
+	}
+}
+
+// RegisterStructValidation registers fn for struct-level rules across fields
+// of the given types, e.g. a start date before an end date. Errors fn reports
+// for a field are keyed by its path, such as "Period.End"; errors reported
+// with an empty field name go to the struct's own path, or to the global
+// errors for the validated struct itself.
+func (v *Validator) RegisterStructValidation(fn validator.StructLevelFunc, types ...any) {
+	v.validator.RegisterStructValidation(fn, types...)
+}
+
+// RegisterCustomTypeFunc makes values of the given types validate as the
+// value fn returns, e.g. the inner string of a sql.NullString, or nil for
+// none, matching how the mapper's converters show them.
+func (v *Validator) RegisterCustomTypeFunc(fn validator.CustomTypeFunc, types ...any) {
+	v.validator.RegisterCustomTypeFunc(fn, types...)
+}
+
+func (v *Validator) RegisterValidation(tag string, fn validator.Func) error {
+	return v.validator.RegisterValidation(tag, fn)
+}
@@ -0,0 +1,844 @@
+package playground
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/locales/de"
+	"github.com/go-playground/locales/en"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	de_translations "github.com/go-playground/validator/v10/translations/de"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+
+	"github.com/omareloui/formmap"
+)
+
+type TestUser struct {
+	ID          string       `validate:"required"`
+	Name        string       `validate:"required,min=3,max=50"`
+	Email       string       `validate:"required,email"`
+	Age         int          `validate:"gte=18,lte=120"`
+	Password    string       `validate:"required,min=8"`
+	ConfirmPass string       `validate:"required,eqfield=Password"`
+	Role        string       `validate:"required,oneof=admin user guest"`
+	Website     string       `validate:"omitempty,url"`
+	Tags        []string     `validate:"max=5,dive,min=2"`
+	Profile     *TestProfile `validate:"omitempty"`
+	Settings    TestSettings
+}
+
+type TestProfile struct {
+	Bio      string    `validate:"max=500"`
+	Birthday time.Time `validate:"required"`
+}
+
+type TestSettings struct {
+	Theme         string `validate:"required,oneof=light dark"`
+	Notifications bool
+}
+
+type TestProduct struct {
+	Name     string        `validate:"required"`
+	Variants []TestVariant `validate:"required,min=1,dive"`
+}
+
+type TestVariant struct {
+	ID    string  `validate:"required"`
+	Name  string  `validate:"required"`
+	Price float64 `validate:"required,gt=0"`
+}
+
+func TestNew(t *testing.T) {
+	v := New()
+
+	if v == nil {
+		t.Fatal("New() returned nil")
+	}
+
+	if v.validator == nil {
+		t.Fatal("validator field is nil")
+	}
+}
+
+func TestValidator_Validate(t *testing.T) {
+	v := New()
+
+	tests := []struct {
+		name      string
+		input     any
+		wantError bool
+		errorKeys []string
+	}{
+		{
+			name: "valid user",
+			input: &TestUser{
+				ID:          "user_123",
+				Name:        "John Doe",
+				Email:       "john@example.com",
+				Age:         25,
+				Password:    "password123",
+				ConfirmPass: "password123",
+				Role:        "user",
+				Settings: TestSettings{
+					Theme: "dark",
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid user - missing required fields",
+			input: &TestUser{
+				Email: "not-an-email",
+				Age:   17,
+				Role:  "superuser",
+			},
+			wantError: true,
+			errorKeys: []string{"ID", "Name", "Email", "Age", "Password", "ConfirmPass", "Role", "Settings.Theme"},
+		},
+		{
+			name: "invalid user - password mismatch",
+			input: &TestUser{
+				ID:          "user_123",
+				Name:        "John",
+				Email:       "john@example.com",
+				Age:         20,
+				Password:    "password123",
+				ConfirmPass: "different",
+				Role:        "admin",
+				Settings: TestSettings{
+					Theme: "light",
+				},
+			},
+			wantError: true,
+			errorKeys: []string{"ConfirmPass"},
+		},
+		{
+			name: "valid product with variants",
+			input: &TestProduct{
+				Name: "Test Product",
+				Variants: []TestVariant{
+					{ID: "v1", Name: "Variant 1", Price: 10.99},
+					{ID: "v2", Name: "Variant 2", Price: 20.99},
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid product - empty variants",
+			input: &TestProduct{
+				Name:     "Test Product",
+				Variants: []TestVariant{},
+			},
+			wantError: true,
+			errorKeys: []string{"Variants"},
+		},
+		{
+			name: "invalid product - invalid variant",
+			input: &TestProduct{
+				Name: "Test Product",
+				Variants: []TestVariant{
+					{ID: "v1", Name: "", Price: -5},
+				},
+			},
+			wantError: true,
+			errorKeys: []string{"Variants[0].Name", "Variants[0].Price"},
+		},
+		{
+			name:      "nil input",
+			input:     nil,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valErr := v.Validate(tt.input)
+
+			if (valErr != nil) != tt.wantError {
+				t.Errorf("Validate() error = %v, wantError %v", valErr, tt.wantError)
+				return
+			}
+
+			if valErr != nil && len(tt.errorKeys) > 0 {
+				for _, key := range tt.errorKeys {
+					if !valErr.HasError(key) {
+						t.Errorf("Expected error for field %s, but not found", key)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestValidator_ParseError(t *testing.T) {
+	v := New()
+
+	user := &TestUser{
+		Email:    "invalid-email",
+		Age:      200,
+		Role:     "invalid-role",
+		Password: "short",
+	}
+
+	valErr := v.Validate(user)
+	if valErr == nil {
+		t.Fatal("Expected validation error, got nil")
+	}
+
+	tests := []struct {
+		field    string
+		hasError bool
+		tag      string
+	}{
+		{"ID", true, "required"},
+		{"Name", true, "required"},
+		{"Email", true, "email"},
+		{"Age", true, "lte"},
+		{"Password", true, "min"},
+		{"Role", true, "oneof"},
+		{"Settings.Theme", true, "required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			if valErr.HasError(tt.field) != tt.hasError {
+				t.Errorf("HasError(%s) = %v, want %v", tt.field, valErr.HasError(tt.field), tt.hasError)
+			}
+
+			if tt.hasError {
+				fieldErr := valErr.Errors[tt.field]
+				if fieldErr.Tag != tt.tag {
+					t.Errorf("Error tag for %s = %v, want %v", tt.field, fieldErr.Tag, tt.tag)
+				}
+			}
+		})
+	}
+}
+
+func TestValidator_ParseError_NilError(t *testing.T) {
+	v := New()
+
+	valErr := v.ParseError(nil)
+
+	if valErr != nil {
+		t.Error("ParseError(nil) should return formmap.ValidationError with initialized Errors map")
+	}
+}
+
+func TestValidator_ParseError_NonValidatorError(t *testing.T) {
+	v := New()
+
+	regularErr := &customError{msg: "some error"}
+
+	valErr := v.ParseError(regularErr)
+
+	if !valErr.HasError("_error") {
+		t.Error("ParseError(non-validator error) should have '_error' field")
+	}
+
+	if valErr.Errors["_error"].Tag != "invalid" {
+		t.Error("ParseError(non-validator error) should set tag to 'invalid'")
+	}
+}
+
+func TestValidator_ErrorHandler(t *testing.T) {
+	errTaken := errors.New("sku taken")
+	v := New(
+		WithErrorHandler(func(err error) *formmap.ValidationError {
+			var syntaxErr *json.SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				return nil
+			}
+			valErr := &formmap.ValidationError{}
+			valErr.AddGlobal(fmt.Sprintf("Malformed JSON at byte %d", syntaxErr.Offset))
+			return valErr
+		}),
+		WithErrorHandler(func(err error) *formmap.ValidationError {
+			if !errors.Is(err, errTaken) {
+				return nil
+			}
+			return formmap.FromFlatMap(map[string][]string{"SKU": {"SKU already taken"}})
+		}),
+	)
+
+	var doc map[string]any
+	syntaxErr := json.Unmarshal([]byte(`{"name":`), &doc)
+	if got := v.ParseError(syntaxErr).Globals(); !slices.Equal(got, []string{"Malformed JSON at byte 8"}) {
+		t.Errorf("ParseError(json error) globals = %v", got)
+	}
+	if got := v.ParseError(fmt.Errorf("saving: %w", errTaken)).MsgFor("SKU"); got != "SKU already taken" {
+		t.Errorf("ParseError(domain error) MsgFor(SKU) = %q", got)
+	}
+	if got := v.ParseError(&customError{msg: "other"}).Errors[formmap.GlobalPath].Tag; got != "invalid" {
+		t.Errorf("ParseError(unhandled error) tag = %q, want invalid", got)
+	}
+}
+
+func TestValidator_CatchAllError(t *testing.T) {
+	err := &customError{msg: "boom"}
+
+	if got := New().ParseError(err).Globals(); !slices.Equal(got, []string{"The submitted data is invalid"}) {
+		t.Errorf("default catch-all = %v", got)
+	}
+
+	v := New(WithCatchAllError("form", "We couldn't read your submission"))
+	valErr := v.ParseError(err)
+	if f := valErr.Errors["form"]; f.Tag != "invalid" || f.Msg() != "We couldn't read your submission" {
+		t.Errorf("Errors[form] = %+v", f)
+	}
+	if valErr.HasError(formmap.GlobalPath) {
+		t.Error("catch-all error still under _error")
+	}
+
+	v = New(WithCatchAllError("", "Please try again"))
+	if got := v.ParseError(err).Globals(); !slices.Equal(got, []string{"Please try again"}) {
+		t.Errorf("Globals() = %v, want the configured message", got)
+	}
+}
+
+func TestValidator_RegisterValidation(t *testing.T) {
+	v := New()
+
+	err := v.RegisterValidation("even", func(fl validator.FieldLevel) bool {
+		num := fl.Field().Int()
+		return num%2 == 0
+	})
+	if err != nil {
+		t.Fatalf("RegisterValidation() error = %v", err)
+	}
+
+	type testStruct struct {
+		Number int `validate:"even"`
+	}
+
+	valErr := v.Validate(&testStruct{Number: 4})
+	if valErr != nil {
+		t.Errorf("Validate() with even number should pass, got error: %v", err)
+	}
+
+	valErr = v.Validate(&testStruct{Number: 5})
+	if valErr == nil {
+		t.Error("Validate() with odd number should fail")
+	}
+}
+
+func TestValidator_NestedStructs(t *testing.T) {
+	v := New()
+
+	type Address struct {
+		Street string `validate:"required"`
+		City   string `validate:"required"`
+	}
+
+	type Person struct {
+		Name    string `validate:"required"`
+		Address Address
+	}
+
+	person := &Person{
+		Name: "John",
+		Address: Address{
+			City: "New York",
+		},
+	}
+
+	valErr := v.Validate(person)
+	if valErr == nil {
+		t.Fatal("Expected validation error")
+	}
+
+	if !valErr.HasError("Address.Street") {
+		t.Error("Should have error for nested field 'address.street'")
+	}
+}
+
+func TestValidator_SliceValidation(t *testing.T) {
+	v := New()
+
+	type Item struct {
+		Name  string `validate:"required"`
+		Value int    `validate:"gt=0"`
+	}
+
+	type Container struct {
+		Items []Item `validate:"required,min=1,max=3,dive"`
+	}
+
+	tests := []struct {
+		name      string
+		container Container
+		wantError bool
+		errorKeys []string
+	}{
+		{
+			name: "valid container",
+			container: Container{
+				Items: []Item{
+					{Name: "Item1", Value: 10},
+					{Name: "Item2", Value: 20},
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "empty items",
+			container: Container{
+				Items: []Item{},
+			},
+			wantError: true,
+			errorKeys: []string{"Items"},
+		},
+		{
+			name: "invalid item in slice",
+			container: Container{
+				Items: []Item{
+					{Name: "Valid", Value: 10},
+					{Name: "", Value: -5},
+				},
+			},
+			wantError: true,
+			errorKeys: []string{"Items[1].Value"},
+		},
+		{
+			name: "too many items",
+			container: Container{
+				Items: []Item{
+					{Name: "Item1", Value: 1},
+					{Name: "Item2", Value: 2},
+					{Name: "Item3", Value: 3},
+					{Name: "Item4", Value: 4},
+				},
+			},
+			wantError: true,
+			errorKeys: []string{"Items"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valErr := v.Validate(&tt.container)
+
+			if (valErr != nil) != tt.wantError {
+				t.Errorf("Validate() error = %v, wantError %v", valErr, tt.wantError)
+				return
+			}
+
+			if valErr != nil && len(tt.errorKeys) > 0 {
+				for _, key := range tt.errorKeys {
+					if !valErr.HasError(key) {
+						t.Errorf("Expected error for field %s, but not found", key)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestValidator_AnonymousStruct(t *testing.T) {
+	v := New()
+
+	input := struct {
+		Name    string `validate:"required"`
+		Address struct {
+			City string `validate:"required"`
+		}
+	}{}
+
+	valErr := v.Validate(&input)
+	for _, key := range []string{"Name", "Address.City"} {
+		if !valErr.HasError(key) {
+			t.Errorf("missing error for %s in %v", key, valErr.Errors)
+		}
+	}
+}
+
+type customError struct {
+	msg string
+}
+
+func (e *customError) Error() string {
+	return e.msg
+}
+
+func TestValidator_RegisterTranslator(t *testing.T) {
+	v := New()
+	if err := v.RegisterValidation("sku", func(fl validator.FieldLevel) bool { return false }); err != nil {
+		t.Fatal(err)
+	}
+
+	trans, _ := ut.New(en.New()).GetTranslator("en")
+	if err := v.RegisterTranslator(trans, en_translations.RegisterDefaultTranslations); err != nil {
+		t.Fatalf("RegisterTranslator() error = %v", err)
+	}
+
+	valErr := v.Validate(struct {
+		Name string `validate:"required"`
+		Code string `validate:"sku"`
+	}{})
+	if valErr == nil {
+		t.Fatal("Validate() = nil, want errors")
+	}
+
+	if got := valErr.MsgFor("Name"); got != "Name is a required field" {
+		t.Errorf("MsgFor(Name) = %q, want the translated message", got)
+	}
+	if f := valErr.Errors["Name"]; f.Tag != "required" {
+		t.Errorf("Errors[Name] = %+v, want tag kept", f)
+	}
+	// Tags without a translation keep the built-in message.
+	if got := valErr.MsgFor("Code"); got != "Validation failed on 'sku' tag" {
+		t.Errorf("MsgFor(Code) = %q, want the built-in message", got)
+	}
+}
+
+func TestValidator_RegisterTranslator_Locale(t *testing.T) {
+	formmap.RegisterMessages("de", map[string]string{"sku": "Ungültige Artikelnummer"})
+	t.Cleanup(func() { formmap.RegisterMessages("de", map[string]string{"sku": ""}) })
+
+	v := New()
+	if err := v.RegisterValidation("sku", func(fl validator.FieldLevel) bool { return false }); err != nil {
+		t.Fatal(err)
+	}
+	trans, _ := ut.New(de.New()).GetTranslator("de")
+	if err := v.RegisterTranslator(trans, de_translations.RegisterDefaultTranslations); err != nil {
+		t.Fatalf("RegisterTranslator() error = %v", err)
+	}
+
+	valErr := v.Validate(struct {
+		Name string `validate:"required"`
+		Code string `validate:"sku"`
+	}{})
+	if valErr.Locale != "de" {
+		t.Errorf("Locale = %q, want de", valErr.Locale)
+	}
+	if got := valErr.Errors["Name"].Message; got != "Name ist ein Pflichtfeld" {
+		t.Errorf("Errors[Name].Message = %q, want the translated message", got)
+	}
+	if got := valErr.MsgFor("Code"); got != "Ungültige Artikelnummer" {
+		t.Errorf("MsgFor(Code) = %q, want the de catalog message", got)
+	}
+}
+
+func TestNew_TagOptions(t *testing.T) {
+	type signup struct {
+		Email string `form:"required,email" label:"email_address"`
+		Name  string `form:"required"`
+		Note  string `validate:"required"`
+	}
+
+	valErr := New(WithTagName("form"), WithFieldNameTag("label")).Validate(&signup{Email: "x"})
+	if valErr == nil {
+		t.Fatal("Validate() = nil, want errors")
+	}
+	if f, ok := valErr.Errors["email_address"]; !ok || f.Tag != "email" {
+		t.Errorf("Errors = %v, want email_address keyed by the label tag", valErr.Errors)
+	}
+	if !valErr.HasError("Name") {
+		t.Errorf("Errors = %v, want Name under its Go name", valErr.Errors)
+	}
+	if valErr.HasError("Note") {
+		t.Error("rules under the validate tag were applied")
+	}
+}
+
+func TestWithJSONKeys(t *testing.T) {
+	type item struct {
+		SKU string `json:"sku" validate:"required"`
+	}
+	type doc struct {
+		Settings struct {
+			Theme string `json:"theme" validate:"required"`
+		} `json:"settings"`
+		Items []item `json:"items" validate:"dive"`
+		Note  string `validate:"required"`
+	}
+	type form struct {
+		Settings struct{ Theme formmap.FormInputData }
+		Items    []struct{ SKU formmap.FormInputData }
+		Note     formmap.FormInputData
+	}
+
+	d := &doc{Items: []item{{}}}
+	valErr := New(WithJSONKeys()).Validate(d)
+	want := []string{"Note", "items[0].sku", "settings.theme"}
+	if got := valErr.Fields(); !slices.Equal(got, want) {
+		t.Fatalf("Fields() = %v, want %v", got, want)
+	}
+	// Paths are indexed, so Go paths find the errors too.
+	for _, path := range []string{"Settings.Theme", "Items[0].SKU"} {
+		if !valErr.HasError(path) {
+			t.Errorf("HasError(%q) = false", path)
+		}
+	}
+
+	f := &form{}
+	if err := formmap.NewMapper(formmap.WithJSONNames()).MapToForm(d, valErr, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+	if f.Settings.Theme.Error == "" || f.Items[0].SKU.Error == "" || f.Note.Error == "" {
+		t.Errorf("form = %+v, want every error mapped", f)
+	}
+}
+
+type testPeriod struct {
+	Start, End int
+}
+
+type testBooking struct {
+	Name   string
+	Period testPeriod
+}
+
+func TestValidator_RegisterStructValidation(t *testing.T) {
+	v := New()
+	v.RegisterStructValidation(func(sl validator.StructLevel) {
+		p := sl.Current().Interface().(testPeriod)
+		if p.End < p.Start {
+			sl.ReportError(p.End, "End", "End", "gtfield", "Start")
+			sl.ReportError(nil, "", "", "daterange", "")
+		}
+	}, testPeriod{})
+	v.RegisterStructValidation(func(sl validator.StructLevel) {
+		if sl.Current().Interface().(testBooking).Name == "closed" {
+			sl.ReportError(nil, "", "", "bookable", "")
+		}
+	}, testBooking{})
+
+	valErr := v.Validate(&testBooking{Name: "closed", Period: testPeriod{Start: 2, End: 1}})
+	for path, tag := range map[string]string{"Period.End": "gtfield", "Period": "daterange", "_error": "bookable"} {
+		if f, ok := valErr.Errors[path]; !ok || f.Tag != tag {
+			t.Errorf("Errors[%q] = %+v, want tag %s (errors %v)", path, f, tag, valErr.Errors)
+		}
+	}
+}
+
+func TestValidator_RegisterCustomTypeFunc(t *testing.T) {
+	type profile struct {
+		Nickname sql.NullString `validate:"required,min=3"`
+	}
+
+	v := New()
+	v.RegisterCustomTypeFunc(func(field reflect.Value) any {
+		if value, err := field.Interface().(driver.Valuer).Value(); err == nil {
+			return value
+		}
+		return nil
+	}, sql.NullString{})
+
+	tests := []struct {
+		nickname sql.NullString
+		wantTag  string
+	}{
+		{sql.NullString{}, "required"},
+		{sql.NullString{String: "ab", Valid: true}, "min"},
+		{sql.NullString{String: "abc", Valid: true}, ""},
+	}
+	for _, tt := range tests {
+		var got string
+		if valErr := v.Validate(&profile{Nickname: tt.nickname}); valErr != nil {
+			got = valErr.Errors["Nickname"].Tag
+		}
+		if got != tt.wantTag {
+			t.Errorf("Validate(%+v) tag = %q, want %q", tt.nickname, got, tt.wantTag)
+		}
+	}
+}
+
+func TestValidator_ValidateMap(t *testing.T) {
+	doc := map[string]any{
+		"title": "",
+		"tags":  []string{"go", ""},
+		"seo":   map[string]any{"slug": "this-slug-is-far-too-long"},
+		"body":  "Hello",
+	}
+	rules := map[string]any{
+		"title": "required",
+		"tags":  "dive,required",
+		"seo":   map[string]any{"slug": "max=10"},
+		"body":  "required",
+	}
+
+	v := New()
+	valErr := v.ValidateMap(doc, rules)
+	want := map[string]string{"title": "required", "tags[1]": "required", "seo.slug": "max"}
+	if len(valErr.Errors) != len(want) {
+		t.Errorf("Errors = %v, want %v", valErr.Errors, want)
+	}
+	for path, tag := range want {
+		if got := valErr.Errors[path].Tag; got != tag {
+			t.Errorf("Errors[%q].Tag = %q, want %q", path, got, tag)
+		}
+	}
+
+	form := &struct {
+		Title formmap.FormInputData `formmap:"title"`
+		Seo   struct {
+			Slug formmap.FormInputData `formmap:"slug"`
+		} `formmap:"seo"`
+	}{}
+	if err := formmap.NewMapper().MapToForm(doc, valErr, form); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+	if form.Title.Error == "" || form.Seo.Slug.Error == "" {
+		t.Errorf("form = %+v, want map errors mapped", form)
+	}
+
+	if valErr := v.ValidateMap(map[string]any{"title": "ok"}, map[string]any{"title": "required"}); valErr != nil {
+		t.Errorf("ValidateMap(valid) = %v, want nil", valErr)
+	}
+}
+
+type testRootEmbed struct {
+	Code string `validate:"required"`
+}
+
+type testRoot struct {
+	testRootEmbed
+	Name string `validate:"required"`
+}
+
+func TestValidator_ParseErrorRoot(t *testing.T) {
+	v := New(WithJSONKeys())
+
+	// A json name matching the type name must not be taken for the root.
+	type Profile struct {
+		Bio string `validate:"required"`
+	}
+	type testProfileDoc struct {
+		Profile Profile `json:"testProfileDoc"`
+	}
+
+	tests := []struct {
+		name string
+		doc  any
+		want []string
+	}{
+		{"named", &testRoot{}, []string{"Name", "testRootEmbed.Code"}},
+		{"anonymous", &struct {
+			Title  string `json:"title" validate:"required"`
+			Author struct {
+				Name string `validate:"required"`
+			} `json:"author"`
+		}{}, []string{"author.Name", "title"}},
+		{"field named like root", &testProfileDoc{}, []string{"testProfileDoc.Bio"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := v.ParseErrorFor(v.validator.Struct(tt.doc), tt.doc).Fields()
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ParseErrorFor fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// The struct namespace of a renamed field doesn't lead its namespace.
+	anon := &struct {
+		Author struct {
+			Name string `validate:"required"`
+		} `json:"author"`
+	}{}
+	got := v.ParseError(v.validator.Struct(anon)).Fields()
+	if want := []string{"author.Name"}; !slices.Equal(got, want) {
+		t.Errorf("ParseError fields = %v, want %v", got, want)
+	}
+}
+
+func TestValidator_MapDivePaths(t *testing.T) {
+	type attr struct {
+		Value string `validate:"required"`
+	}
+	type doc struct {
+		Attrs map[string]attr   `json:"attrs" validate:"dive,keys,min=3,endkeys"`
+		Tags  map[string]string `json:"tags" validate:"dive,required"`
+	}
+	type attrForm struct {
+		Value formmap.FormInputData
+	}
+	type form struct {
+		Attrs map[string]attrForm
+		Tags  map[string]formmap.FormInputData
+	}
+
+	d := &doc{
+		Attrs: map[string]attr{"color": {}, "v1.2": {}, "ok": {Value: "x"}},
+		Tags:  map[string]string{"a.b": ""},
+	}
+	v := New(WithJSONKeys())
+	valErr := v.Validate(d)
+
+	want := []string{"attrs[color].Value", "attrs[ok]", "attrs[v1.2].Value", "tags[a.b]"}
+	if got := valErr.Fields(); !slices.Equal(got, want) {
+		t.Fatalf("Fields() = %v, want %v", got, want)
+	}
+	if !valErr.HasError("Attrs[v1.2].Value") || !valErr.AnyErrorUnder("Attrs[v1.2]") {
+		t.Error("error under Attrs[v1.2] not found by Go path")
+	}
+
+	f := &form{}
+	if err := formmap.NewMapper().MapToForm(d, valErr, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+	if got := f.Attrs["v1.2"].Value.Error; got != "This field is required" {
+		t.Errorf("Attrs[v1.2].Value error = %q", got)
+	}
+	if got := f.Attrs["ok"].Value.Error; got != "" {
+		t.Errorf("Attrs[ok].Value error = %q, want none", got)
+	}
+	if got := f.Tags["a.b"].Error; got != "This field is required" {
+		t.Errorf("Tags[a.b] error = %q", got)
+	}
+}
+
+func TestValidator_RejectedValue(t *testing.T) {
+	v := New()
+	valErr := v.Validate(struct {
+		Qty      string `validate:"numeric"`
+		Password string `validate:"min=8"`
+	}{Qty: "abc", Password: "hunter2"})
+
+	f := valErr.Errors["Qty"]
+	if f.Value != "abc" || f.Kind != reflect.String {
+		t.Errorf("Errors[Qty] Value, Kind = %v, %v, want abc, string", f.Value, f.Kind)
+	}
+
+	data, err := json.Marshal(valErr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "hunter2") {
+		t.Errorf("JSON %s carries the rejected value", data)
+	}
+
+	valErr = v.ValidateMap(map[string]any{"qty": "abc"}, map[string]any{"qty": "numeric"})
+	if f := valErr.Errors["qty"]; f.Value != "abc" {
+		t.Errorf("ValidateMap Errors[qty].Value = %v, want abc", f.Value)
+	}
+}
+
+func TestValidator_LengthMessagesByKind(t *testing.T) {
+	valErr := New().Validate(struct {
+		Name string   `validate:"min=3"`
+		Age  int      `validate:"min=18"`
+		Tags []string `validate:"min=2"`
+	}{Name: "Al", Age: 12, Tags: []string{"a"}})
+
+	want := map[string]string{
+		"Name": "Must be at least 3 characters",
+		"Age":  "Must be at least 18",
+		"Tags": "Must be at least 2 items",
+	}
+	for path, msg := range want {
+		if got := valErr.MsgFor(path); got != msg {
+			t.Errorf("MsgFor(%s) = %q, want %q", path, got, msg)
+		}
+	}
+}
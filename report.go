@@ -0,0 +1,115 @@
+package formmap
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MapReport records what a mapping did, field by field.
+type MapReport struct {
+	Entries []ReportEntry
+}
+
+type ReportEntry struct {
+	Path string
+	// Conversion is how the value was produced; empty for skipped fields.
+	Conversion Conversion
+	Value      string
+	Error      string
+	Skipped    bool
+	Reason     string
+}
+
+// Entry returns the entry for fieldPath.
+func (r *MapReport) Entry(fieldPath string) (ReportEntry, bool) {
+	for _, e := range r.Entries {
+		if e.Path == fieldPath {
+			return e, true
+		}
+	}
+	return ReportEntry{}, false
+}
+
+// Skipped returns the entries of fields that were not mapped.
+func (r *MapReport) Skipped() []ReportEntry {
+	var skipped []ReportEntry
+	for _, e := range r.Entries {
+		if e.Skipped {
+			skipped = append(skipped, e)
+		}
+	}
+	return skipped
+}
+
+// MapToFormDryRun maps doc into a scratch copy of formData's type and reports
+// each path visited, how it was converted and which fields were skipped and
+// why. formData and err are left untouched; hooks run against the copies.
+// At most one MapOptions may be passed, as for MapToFormWithOptions.
+func (m *Mapper) MapToFormDryRun(doc any, err error, formData any, opts ...MapOptions) (*MapReport, error) {
+	formVal := reflect.ValueOf(formData)
+	if formVal.Kind() != reflect.Ptr {
+		return nil, fmt.Errorf("%w: formData must be a pointer", ErrNotPointer)
+	}
+	if formVal.IsNil() {
+		return nil, fmt.Errorf("%w: formData cannot be nil", ErrNilInput)
+	}
+	scratch := reflect.New(formVal.Elem().Type()).Interface()
+
+	if valErr, ok := err.(*ValidationError); ok && valErr != nil {
+		err = valErr.clone()
+	}
+
+	st := &mapState{}
+	if len(opts) > 0 {
+		var stErr error
+		if st, stErr = newOptionsState(opts[0]); stErr != nil {
+			return nil, stErr
+		}
+	}
+	st.report = &MapReport{}
+
+	if mapErr := m.mapToForm(doc, err, scratch, st); mapErr != nil {
+		return st.report, mapErr
+	}
+	return st.report, nil
+}
+
+func (st *mapState) reportSkip(fieldPath pathKey, reason string) {
+	if st.report == nil {
+		return
+	}
+	st.report.Entries = append(st.report.Entries, ReportEntry{Path: fieldPath.String(), Skipped: true, Reason: reason})
+}
+
+// reportSkipf formats the reason only when a report is being built.
+func (st *mapState) reportSkipf(fieldPath pathKey, format, arg string) {
+	if st.report == nil {
+		return
+	}
+	st.reportSkip(fieldPath, fmt.Sprintf(format, arg))
+}
+
+func (st *mapState) reportMapped(fieldPath pathKey, c Conversion, formVal reflect.Value) {
+	if st.report == nil {
+		return
+	}
+
+	entry := ReportEntry{Path: fieldPath.String(), Conversion: c}
+	if ff, ok := asFormField(formVal); ok {
+		entry.Value = ff.FormValue()
+		if f := formVal.FieldByName("Error"); formVal.Kind() == reflect.Struct && f.IsValid() && f.Kind() == reflect.String {
+			entry.Error = f.String()
+		}
+	}
+	st.report.Entries = append(st.report.Entries, entry)
+}
+
+func (m *Mapper) valueConversion(v reflect.Value, fc FieldConfig) Conversion {
+	for v.IsValid() && (v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr) && !v.IsNil() {
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return ConversionKind
+	}
+	return m.leafConversion(v.Type(), fc)
+}
@@ -0,0 +1,119 @@
+package formmap
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMapper_MapToFormDryRun(t *testing.T) {
+	type item struct{ Name string }
+	type doc struct {
+		Title    string
+		Price    float64
+		Secret   string `formmap:"-"`
+		Nickname string
+		Items    []item
+		Code     string
+	}
+	type docForm struct {
+		Title FormInputData
+		Price FormInputData
+		Items []struct{ Name FormInputData }
+		Code  FormInputData
+	}
+
+	mapper := NewMapper()
+	mapper.RegisterFieldMapper("Code", func(docField, formField reflect.Value, fieldPath string, valErr *ValidationError) error {
+		formField.FieldByName("Value").SetString("#" + docField.String())
+		return nil
+	})
+	mapper.BeforeMap(func(doc, form any, valErr *ValidationError) error {
+		valErr.Errors["Price"] = ValidationField{Tag: "required"}
+		return nil
+	})
+
+	d := &doc{Title: "Hat", Price: 9.5, Nickname: "h", Items: []item{{"a"}, {"b"}}, Code: "x1"}
+	valErr := &ValidationError{Errors: Errors{"Title": ValidationField{Tag: "required"}}}
+	f := &docForm{Title: FormInputData{Value: "keep"}}
+
+	report, err := mapper.MapToFormDryRun(d, valErr, f, MapOptions{SkipFields: []string{"Items[1]"}})
+	if err != nil {
+		t.Fatalf("MapToFormDryRun() error = %v", err)
+	}
+
+	if f.Title.Value != "keep" || f.Price.Value != "" {
+		t.Errorf("form was mutated: %+v", f)
+	}
+	if len(valErr.Errors) != 1 {
+		t.Errorf("validation errors were mutated: %v", valErr.Errors)
+	}
+
+	tests := []struct {
+		path  string
+		want  ReportEntry
+		found bool
+	}{
+		{"Title", ReportEntry{Path: "Title", Conversion: ConversionKind, Value: "Hat", Error: "This field is required"}, true},
+		{"Price", ReportEntry{Path: "Price", Conversion: ConversionConverter, Value: "9.5", Error: "This field is required"}, true},
+		{"Secret", ReportEntry{Path: "Secret", Skipped: true, Reason: "skipped by formmap tag or config"}, true},
+		{"Nickname", ReportEntry{Path: "Nickname", Skipped: true, Reason: "no form field Nickname"}, true},
+		{"Items[0].Name", ReportEntry{Path: "Items[0].Name", Conversion: ConversionKind, Value: "a"}, true},
+		{"Items[1]", ReportEntry{Path: "Items[1]", Skipped: true, Reason: "skipped by MapOptions.SkipFields"}, true},
+		{"Items[1].Name", ReportEntry{}, false},
+		{"Code", ReportEntry{Path: "Code", Conversion: ConversionFieldMapper, Value: "#x1"}, true},
+	}
+
+	for _, tt := range tests {
+		got, ok := report.Entry(tt.path)
+		if ok != tt.found || got != tt.want {
+			t.Errorf("Entry(%q) = %+v, %v; want %+v, %v", tt.path, got, ok, tt.want, tt.found)
+		}
+	}
+
+	if n := len(report.Skipped()); n != 3 {
+		t.Errorf("Skipped() has %d entries, want 3", n)
+	}
+}
+
+func TestMapper_MapToFormDryRun_ErrorsAsMapped(t *testing.T) {
+	RegisterMessages("ar", map[string]string{"required": "هذا الحقل مطلوب"})
+	t.Cleanup(func() { RegisterMessages("ar", map[string]string{"required": ""}) })
+
+	type doc struct {
+		Title    string
+		Settings struct {
+			Theme string `json:"theme"`
+		} `json:"settings"`
+	}
+	type docForm struct {
+		Title    FormInputData
+		Settings struct{ Theme FormInputData }
+	}
+
+	d := &doc{}
+	valErr := (&ValidationError{Locale: "ar", Errors: Errors{
+		"Title":          ValidationField{Tag: "required"},
+		"settings.theme": ValidationField{Tag: "min", Param: "3"},
+	}}).IndexPaths(d)
+
+	report, err := NewMapper().MapToFormDryRun(d, valErr, &docForm{})
+	if err != nil {
+		t.Fatalf("MapToFormDryRun() error = %v", err)
+	}
+	f := &docForm{}
+	if err := NewMapper().MapToForm(d, valErr, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	for path, want := range map[string]string{"Title": f.Title.Error, "Settings.Theme": f.Settings.Theme.Error} {
+		if want == "" {
+			t.Fatalf("MapToForm left %s without an error", path)
+		}
+		if got, _ := report.Entry(path); got.Error != want {
+			t.Errorf("Entry(%q).Error = %q, want %q as MapToForm renders it", path, got.Error, want)
+		}
+	}
+	if f.Title.Error != "هذا الحقل مطلوب" {
+		t.Errorf("Title error = %q, want the ar message", f.Title.Error)
+	}
+}
@@ -0,0 +1,77 @@
+package formmap
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ResetForm clears every Value and Error in form, recursively, so the struct
+// can be reused for another request. Slices are truncated rather than
+// released, letting the next MapToForm reuse their backing arrays.
+func ResetForm(form any) error {
+	v := reflect.ValueOf(form)
+	if v.Kind() != reflect.Ptr {
+		return fmt.Errorf("%w: form must be a pointer", ErrNotPointer)
+	}
+	if v.IsNil() {
+		return fmt.Errorf("%w: form cannot be nil", ErrNilInput)
+	}
+
+	resetValue(v.Elem())
+	return nil
+}
+
+func resetValue(v reflect.Value) {
+	if !v.CanSet() {
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			resetValue(v.Field(i))
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			v.Index(i).SetZero()
+		}
+		v.SetLen(0)
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			resetValue(v.Index(i))
+		}
+	case reflect.Ptr:
+		if !v.IsNil() {
+			resetValue(v.Elem())
+		}
+	default:
+		v.SetZero()
+	}
+}
+
+// FormPool hands out reusable form structs. Forms are reset when they are
+// returned with Put.
+type FormPool[T any] struct {
+	pool sync.Pool
+}
+
+func NewFormPool[T any]() *FormPool[T] {
+	return &FormPool[T]{
+		pool: sync.Pool{
+			New: func() any { return new(T) },
+		},
+	}
+}
+
+func (p *FormPool[T]) Get() *T {
+	return p.pool.Get().(*T)
+}
+
+func (p *FormPool[T]) Put(form *T) {
+	if form == nil {
+		return
+	}
+	resetValue(reflect.ValueOf(form).Elem())
+	p.pool.Put(form)
+}
@@ -0,0 +1,76 @@
+package formmap
+
+import "testing"
+
+func TestResetForm(t *testing.T) {
+	mapper := NewMapper()
+
+	doc := &TestDocument{
+		Name: "Widget",
+		Tags: []string{"a", "b"},
+		Metadata: TestMetadata{
+			Version: "1.0",
+		},
+		Items:     []TestItem{{ItemID: "1"}},
+		NestedPtr: &TestMetadata{Author: "Jane"},
+	}
+	valErr := &ValidationError{Errors: Errors{"Name": ValidationField{Tag: "required"}}}
+	form := &TestFormData{}
+
+	if err := mapper.MapToForm(doc, valErr, form); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	tagsCap := cap(form.Tags)
+
+	if err := ResetForm(form); err != nil {
+		t.Fatalf("ResetForm() error = %v", err)
+	}
+
+	if form.Name != (FormInputData{}) {
+		t.Errorf("Name = %+v, want zero", form.Name)
+	}
+	if form.Metadata.Version != (FormInputData{}) {
+		t.Errorf("Metadata.Version = %+v, want zero", form.Metadata.Version)
+	}
+	if len(form.Tags) != 0 || cap(form.Tags) != tagsCap {
+		t.Errorf("Tags len/cap = %d/%d, want 0/%d", len(form.Tags), cap(form.Tags), tagsCap)
+	}
+	if form.NestedPtr == nil || form.NestedPtr.Author != (FormInputData{}) {
+		t.Errorf("NestedPtr = %+v, want reset struct", form.NestedPtr)
+	}
+
+	if err := mapper.MapToForm(&TestDocument{Tags: []string{"x"}}, nil, form); err != nil {
+		t.Fatalf("MapToForm() after reset error = %v", err)
+	}
+	if len(form.Tags) != 1 || form.Tags[0].Value != "x" {
+		t.Errorf("Tags = %+v, want [x]", form.Tags)
+	}
+}
+
+func TestResetForm_InvalidInput(t *testing.T) {
+	if err := ResetForm(TestFormData{}); err == nil {
+		t.Error("expected error for non-pointer form")
+	}
+	if err := ResetForm((*TestFormData)(nil)); err == nil {
+		t.Error("expected error for nil form")
+	}
+}
+
+func TestFormPool(t *testing.T) {
+	pool := NewFormPool[TestFormData]()
+
+	form := pool.Get()
+	form.Name.Value = "Widget"
+	form.Name.Error = "bad"
+	form.Tags = append(form.Tags, FormInputData{Value: "a"})
+	pool.Put(form)
+
+	if form.Name != (FormInputData{}) || len(form.Tags) != 0 {
+		t.Errorf("Put() did not reset form: %+v", form)
+	}
+
+	if got := pool.Get(); got == nil {
+		t.Fatal("Get() returned nil")
+	}
+}
@@ -0,0 +1,397 @@
+package formmap
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ValueParser func(s string, v reflect.Value) error
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+var timeInputLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// defaultParsers returns the built-in parsers.
+func (m *Mapper) defaultParsers() map[reflect.Type]ValueParser {
+	return map[reflect.Type]ValueParser{
+		reflect.TypeOf(time.Duration(0)): func(s string, v reflect.Value) error {
+			d, err := parseDuration(s, m.durationUnit)
+			if err != nil {
+				return err
+			}
+			v.SetInt(int64(d))
+			return nil
+		},
+	}
+}
+
+// parseTime parses s in the mapper's time layout or one of the common input
+// layouts. Times without an offset are read as wall-clock times in loc;
+// those with one, such as "2024-01-01T10:00:00Z", keep it.
+func (m *Mapper) parseTime(s string, loc *time.Location) (time.Time, error) {
+	for _, layout := range append([]string{m.TimeLayout()}, timeInputLayouts...) {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q as time", s)
+}
+
+func (m *Mapper) RegisterParser(t reflect.Type, parser ValueParser) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.parsers[t] = parser
+}
+
+// RegisterParserFor registers fn as the parser for T, the reverse of
+// RegisterConverterFor.
+func RegisterParserFor[T any](m *Mapper, fn func(string) (T, error)) {
+	m.RegisterParser(reflect.TypeFor[T](), func(s string, v reflect.Value) error {
+		parsed, err := fn(s)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(&parsed).Elem())
+		return nil
+	})
+}
+
+func (m *Mapper) parserFor(t reflect.Type) ValueParser {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.parsers[t]
+}
+
+func (m *Mapper) MapFromForm(formData any, doc any) error {
+	formVal := reflect.ValueOf(formData)
+	docVal := reflect.ValueOf(doc)
+
+	if docVal.Kind() != reflect.Ptr || formVal.Kind() != reflect.Ptr {
+		return fmt.Errorf("%w: doc and formData must be pointers", ErrNotPointer)
+	}
+
+	if docVal.IsNil() || formVal.IsNil() {
+		return fmt.Errorf("%w: doc and formData cannot be nil", ErrNilInput)
+	}
+
+	// Map documents are read by MapToForm only; there are no fields to fill.
+	if docVal.Elem().Kind() != reflect.Struct || formVal.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: doc and formData must point to structs, got %s and %s", ErrNotStruct, docVal.Type(), formVal.Type())
+	}
+
+	st := &unmapState{location: m.location}
+	if m.locale != "" {
+		loc, ok := LookupLocale(m.locale)
+		if !ok {
+			return fmt.Errorf("%w: unknown locale %q", ErrInvalidOption, m.locale)
+		}
+		st.locale = &loc
+	}
+
+	if !validDurationUnit(m.durationUnit) {
+		return fmt.Errorf("%w: unknown duration unit %q", ErrInvalidOption, m.durationUnit)
+	}
+
+	if !validBytesEncoding(m.bytesEncoding) {
+		return fmt.Errorf("%w: unknown bytes encoding %q", ErrInvalidOption, m.bytesEncoding)
+	}
+
+	return m.unmapStruct(st, formVal.Elem(), docVal.Elem(), rootPath(&st.path))
+}
+
+type unmapState struct {
+	locale   *Locale
+	location *time.Location
+
+	// path backs the pathKeys of the call.
+	path []byte
+}
+
+func (st *unmapState) parseNumber(s string) string {
+	if st.locale == nil {
+		return s
+	}
+	return st.locale.ParseNumber(s)
+}
+
+func (m *Mapper) unmapStruct(st *unmapState, formVal, docVal reflect.Value, pathPrefix pathKey) error {
+	docType := docVal.Type()
+	formType := formVal.Type()
+
+	for _, plan := range m.structPlan(docType, formType) {
+		docFieldVal := docVal.Field(plan.index)
+		if !docFieldVal.CanSet() {
+			continue
+		}
+
+		fieldPath, formFieldName, fc, skip := m.resolvePath(plan.tag, pathPrefix)
+		if skip {
+			continue
+		}
+
+		formField, found := m.planFormField(plan, formType, formFieldName)
+		if !found {
+			continue
+		}
+
+		formFieldVal := formVal.FieldByIndex(formField.Index)
+
+		if err := m.unmapField(st, formFieldVal, docFieldVal, fieldPath, fc); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *Mapper) unmapField(st *unmapState, formFieldVal, docFieldVal reflect.Value, fieldPath pathKey, fc FieldConfig) error {
+	if m.isFormField(formFieldVal.Type()) {
+		ff, ok := asFormField(formFieldVal)
+		if !ok {
+			return fmt.Errorf("form field %s is not addressable", fieldPath)
+		}
+		value := ff.FormValue()
+		if err := m.parseValue(st, value, docFieldVal, fc); err != nil {
+			return &FieldError{Path: fieldPath.String(), Kind: ErrParseFailed, Err: err}
+		}
+		return nil
+	}
+
+	// Mirrors mapField: a pointer document field may be held by value on the
+	// form side.
+	if docFieldVal.Kind() == reflect.Ptr && formFieldVal.Kind() != reflect.Ptr {
+		if docFieldVal.IsNil() {
+			docFieldVal.Set(reflect.New(docFieldVal.Type().Elem()))
+		}
+		return m.unmapField(st, formFieldVal, docFieldVal.Elem(), fieldPath, fc)
+	}
+
+	if isList(formFieldVal) && isList(docFieldVal) {
+		return m.unmapSlice(st, formFieldVal, docFieldVal, fieldPath, fc)
+	}
+
+	if formFieldVal.Kind() == reflect.Map && docFieldVal.Kind() == reflect.Map {
+		return m.unmapMap(st, formFieldVal, docFieldVal, fieldPath, fc)
+	}
+
+	if formFieldVal.Kind() == reflect.Struct && docFieldVal.Kind() == reflect.Struct {
+		return m.unmapStruct(st, formFieldVal, docFieldVal, fieldPath)
+	}
+
+	if formFieldVal.Kind() == reflect.Ptr && docFieldVal.Kind() == reflect.Ptr {
+		if formFieldVal.IsNil() {
+			docFieldVal.Set(reflect.Zero(docFieldVal.Type()))
+			return nil
+		}
+
+		if docFieldVal.IsNil() {
+			docFieldVal.Set(reflect.New(docFieldVal.Type().Elem()))
+		}
+
+		return m.unmapField(st, formFieldVal.Elem(), docFieldVal.Elem(), fieldPath, fc)
+	}
+
+	return nil
+}
+
+func (m *Mapper) unmapSlice(st *unmapState, formSlice, docSlice reflect.Value, fieldPath pathKey, fc FieldConfig) error {
+	n := formSlice.Len()
+
+	if docSlice.Kind() == reflect.Array {
+		docSlice.SetZero()
+		n = min(n, docSlice.Len())
+	} else if formSlice.Kind() == reflect.Slice && formSlice.IsNil() {
+		docSlice.SetZero()
+		return nil
+	} else {
+		docSlice.Set(reflect.MakeSlice(docSlice.Type(), n, n))
+	}
+
+	for i := 0; i < n; i++ {
+		indexedPath := fieldPath.index(i)
+		if err := m.unmapField(st, formSlice.Index(i), docSlice.Index(i), indexedPath, m.elemConfig(fc, indexedPath)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *Mapper) unmapMap(st *unmapState, formMap, docMap reflect.Value, fieldPath pathKey, fc FieldConfig) error {
+	if formMap.IsNil() {
+		docMap.SetZero()
+		return nil
+	}
+
+	docType := docMap.Type()
+	newMap := reflect.MakeMapWithSize(docType, formMap.Len())
+
+	iter := formMap.MapRange()
+	for iter.Next() {
+		keyPath := fieldPath.key(iter.Key())
+
+		key := reflect.New(docType.Key()).Elem()
+		if iter.Key().Type() == docType.Key() {
+			key.Set(iter.Key())
+		} else if err := m.parseValue(st, fmt.Sprint(iter.Key().Interface()), key, FieldConfig{}); err != nil {
+			return &FieldError{Path: keyPath.String(), Kind: ErrParseFailed, Err: err}
+		}
+
+		// Map elements aren't addressable, so copy the form element out first.
+		formElem := reflect.New(iter.Value().Type()).Elem()
+		formElem.Set(iter.Value())
+
+		docElem := reflect.New(docType.Elem()).Elem()
+		if err := m.unmapField(st, formElem, docElem, keyPath, m.elemConfig(fc, keyPath)); err != nil {
+			return err
+		}
+
+		newMap.SetMapIndex(key, docElem)
+	}
+
+	docMap.Set(newMap)
+	return nil
+}
+
+// parseList fills a slice from a single joined form value.
+func (m *Mapper) parseList(st *unmapState, s string, v reflect.Value, fc FieldConfig) error {
+	if !isScalarList(reflect.New(v.Type()).Elem()) {
+		return fmt.Errorf("%w %s", ErrUnsupportedType, v.Type())
+	}
+
+	parts := splitList(s, fc.separator())
+	list := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+	for i, part := range parts {
+		if err := m.parseValue(st, part, list.Index(i), FieldConfig{}); err != nil {
+			return fmt.Errorf("item %d: %w", i, err)
+		}
+	}
+	v.Set(list)
+	return nil
+}
+
+func (m *Mapper) parseValue(st *unmapState, s string, v reflect.Value, fc FieldConfig) error {
+	if err := m.parseRawValue(st, s, v, fc); err != nil {
+		return err
+	}
+
+	// Times are stored in UTC, whatever zone they were entered in.
+	if st.location != nil && v.Type() == timeType {
+		v.Set(reflect.ValueOf(v.Interface().(time.Time).UTC()))
+	}
+	return nil
+}
+
+// zone returns the location times without an offset are read in.
+func (st *unmapState) zone() *time.Location {
+	if st.location == nil {
+		return time.UTC
+	}
+	return st.location
+}
+
+func (m *Mapper) parseRawValue(st *unmapState, s string, v reflect.Value, fc FieldConfig) error {
+	s = strings.TrimSpace(s)
+
+	if s == "" {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	if v.Kind() == reflect.Ptr {
+		elem := reflect.New(v.Type().Elem())
+		if err := m.parseValue(st, s, elem.Elem(), fc); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+
+	if handled, err := fc.parse(s, v, st.zone()); handled {
+		return err
+	}
+
+	if parser := m.parserFor(v.Type()); parser != nil {
+		if _, _, localizable := m.convertersFor(v.Type()); localizable {
+			s = st.parseNumber(s)
+		}
+		return parser(s, v)
+	}
+
+	if v.Type() == timeType {
+		t, err := m.parseTime(s, st.zone())
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	if isSQLNull(v.Type()) {
+		if err := m.parseValue(st, s, v.Field(0), fc); err != nil {
+			return err
+		}
+		v.FieldByName("Valid").SetBool(true)
+		return nil
+	}
+
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(st.parseNumber(s), 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(st.parseNumber(s), 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(st.parseNumber(s), v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Bool:
+		// Checked checkboxes submit "on" unless they carry a value attribute.
+		if s == "on" {
+			v.SetBool(true)
+			return nil
+		}
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Slice:
+		if !isBytes(v.Type()) {
+			return m.parseList(st, s, v, fc)
+		}
+		b, err := m.decodeBytes(s)
+		if err != nil {
+			return err
+		}
+		v.SetBytes(b)
+	default:
+		return fmt.Errorf("%w %s", ErrUnsupportedType, v.Type())
+	}
+
+	return nil
+}
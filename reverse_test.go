@@ -0,0 +1,224 @@
+package formmap
+
+import (
+	"errors"
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testLevel int
+
+func (l *testLevel) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "low":
+		*l = 1
+	case "high":
+		*l = 2
+	default:
+		return &time.ParseError{Value: string(text), Message: ": unknown level"}
+	}
+	return nil
+}
+
+type TestReverseDoc struct {
+	Level testLevel
+	Addr  netip.Addr
+	Count *int
+	Ratio float32
+}
+
+type TestReverseForm struct {
+	Level FormInputData
+	Addr  FormInputData
+	Count FormInputData
+	Ratio FormInputData
+}
+
+func TestMapper_MapFromForm(t *testing.T) {
+	mapper := NewMapper()
+
+	form := &TestFormData{
+		ID:          FormInputData{Value: "123"},
+		Name:        FormInputData{Value: "Test Product"},
+		Price:       FormInputData{Value: "99.99"},
+		Quantity:    FormInputData{Value: " 10 "},
+		IsActive:    FormInputData{Value: "on"},
+		CreatedAt:   FormInputData{Value: "2024-01-01T12:00:00Z"},
+		Duration:    FormInputData{Value: "90"},
+		Tags:        []FormInputData{{Value: "a"}, {Value: "b"}},
+		Metadata:    TestMetadataForm{Version: FormInputData{Value: "1.0"}},
+		Items:       []TestItemForm{{ItemID: FormInputData{Value: "i1"}, Price: FormInputData{Value: "2.5"}}},
+		OptionalPtr: FormInputData{Value: "opt"},
+		NestedPtr:   &TestMetadataForm{Author: FormInputData{Value: "Jane"}},
+	}
+
+	doc := &TestDocument{Description: "cleared"}
+
+	if err := mapper.MapFromForm(form, doc); err != nil {
+		t.Fatalf("MapFromForm() error = %v", err)
+	}
+
+	opt := "opt"
+	want := &TestDocument{
+		ID:          "123",
+		Name:        "Test Product",
+		Price:       99.99,
+		Quantity:    10,
+		IsActive:    true,
+		CreatedAt:   time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		Duration:    90 * time.Minute,
+		Tags:        []string{"a", "b"},
+		Metadata:    TestMetadata{Version: "1.0"},
+		Items:       []TestItem{{ItemID: "i1", Price: 2.5}},
+		OptionalPtr: &opt,
+		NestedPtr:   &TestMetadata{Author: "Jane"},
+	}
+
+	if !reflect.DeepEqual(doc, want) {
+		t.Errorf("MapFromForm() doc = %+v, want %+v", doc, want)
+	}
+}
+
+func TestMapper_MapFromForm_RoundTrip(t *testing.T) {
+	mapper := NewMapper()
+
+	original := &TestDocument{
+		Name:      "Widget",
+		Price:     12.5,
+		Quantity:  3,
+		IsActive:  true,
+		CreatedAt: time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC),
+		Duration:  45 * time.Minute,
+		Items:     []TestItem{{ItemID: "1", ItemName: "One", Price: 1.25}},
+	}
+
+	form := &TestFormData{}
+	if err := mapper.MapToForm(original, nil, form); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	got := &TestDocument{}
+	if err := mapper.MapFromForm(form, got); err != nil {
+		t.Fatalf("MapFromForm() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, original) {
+		t.Errorf("round trip = %+v, want %+v", got, original)
+	}
+}
+
+func TestMapper_MapFromForm_TextUnmarshaler(t *testing.T) {
+	mapper := NewMapper()
+
+	form := &TestReverseForm{
+		Level: FormInputData{Value: "High"},
+		Addr:  FormInputData{Value: "192.168.1.10"},
+		Count: FormInputData{Value: ""},
+		Ratio: FormInputData{Value: "0.5"},
+	}
+	doc := &TestReverseDoc{}
+
+	if err := mapper.MapFromForm(form, doc); err != nil {
+		t.Fatalf("MapFromForm() error = %v", err)
+	}
+
+	if doc.Level != 2 {
+		t.Errorf("Level = %v, want 2", doc.Level)
+	}
+	if doc.Addr != netip.MustParseAddr("192.168.1.10") {
+		t.Errorf("Addr = %v, want 192.168.1.10", doc.Addr)
+	}
+	if doc.Count != nil {
+		t.Errorf("Count = %v, want nil", *doc.Count)
+	}
+	if doc.Ratio != 0.5 {
+		t.Errorf("Ratio = %v, want 0.5", doc.Ratio)
+	}
+}
+
+func TestMapper_MapFromForm_Errors(t *testing.T) {
+	mapper := NewMapper()
+
+	tests := []struct {
+		name string
+		form any
+		doc  any
+	}{
+		{"invalid int", &TestFormData{Quantity: FormInputData{Value: "ten"}}, &TestDocument{}},
+		{"invalid time", &TestFormData{CreatedAt: FormInputData{Value: "yesterday"}}, &TestDocument{}},
+		{"invalid text", &TestReverseForm{Level: FormInputData{Value: "medium"}}, &TestReverseDoc{}},
+		{"non-pointer doc", &TestFormData{}, TestDocument{}},
+		{"nil form", (*TestFormData)(nil), &TestDocument{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := mapper.MapFromForm(tt.form, tt.doc); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestMapper_MapFromForm_NotStruct(t *testing.T) {
+	n := 0
+	tests := []struct {
+		name string
+		form any
+		doc  any
+	}{
+		{"map doc", &TestFormData{}, &map[string]any{}},
+		{"int doc", &TestFormData{}, &n},
+		{"int form", &n, &TestDocument{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := NewMapper().MapFromForm(tt.form, tt.doc); !errors.Is(err, ErrNotStruct) {
+				t.Errorf("MapFromForm() error = %v, want ErrNotStruct", err)
+			}
+		})
+	}
+}
+
+func TestMapper_RegisterParser(t *testing.T) {
+	mapper := NewMapper()
+	mapper.RegisterParser(reflect.TypeOf(time.Duration(0)), func(s string, v reflect.Value) error {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	})
+
+	doc := &TestDocument{}
+	if err := mapper.MapFromForm(&TestFormData{Duration: FormInputData{Value: "2h30m"}}, doc); err != nil {
+		t.Fatalf("MapFromForm() error = %v", err)
+	}
+
+	if doc.Duration != 150*time.Minute {
+		t.Errorf("Duration = %v, want 2h30m", doc.Duration)
+	}
+}
+
+func TestRegisterParserFor(t *testing.T) {
+	mapper := NewMapper()
+	RegisterParserFor(mapper, time.ParseDuration)
+
+	doc := &TestDocument{}
+	if err := mapper.MapFromForm(&TestFormData{Duration: FormInputData{Value: "1m30s"}}, doc); err != nil {
+		t.Fatalf("MapFromForm() error = %v", err)
+	}
+
+	if doc.Duration != 90*time.Second {
+		t.Errorf("Duration = %v, want 1m30s", doc.Duration)
+	}
+
+	if err := mapper.MapFromForm(&TestFormData{Duration: FormInputData{Value: "soon"}}, doc); err == nil {
+		t.Error("expected parse error, got nil")
+	}
+}
@@ -0,0 +1,28 @@
+package formmap
+
+import (
+	"reflect"
+)
+
+// sqlNullValue unwraps database/sql null types such as sql.NullString,
+// sql.NullTime and sql.Null[T]. ok reports whether v is one; inner is
+// invalid when the value is NULL.
+func sqlNullValue(v reflect.Value) (inner reflect.Value, ok bool) {
+	if !isSQLNull(v.Type()) {
+		return reflect.Value{}, false
+	}
+	if !v.FieldByName("Valid").Bool() {
+		return reflect.Value{}, true
+	}
+	return v.Field(0), true
+}
+
+// isSQLNull matches the database/sql structs that pair a value with a Valid
+// flag.
+func isSQLNull(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t.PkgPath() != "database/sql" || t.NumField() != 2 {
+		return false
+	}
+	valid := t.Field(1)
+	return valid.Name == "Valid" && valid.Type.Kind() == reflect.Bool
+}
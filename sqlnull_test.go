@@ -0,0 +1,80 @@
+package formmap
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestMapper_SQLNullTypes(t *testing.T) {
+	type row struct {
+		Name    sql.NullString
+		Age     sql.NullInt64
+		Score   sql.NullFloat64
+		Active  sql.NullBool
+		Born    sql.NullTime `formmap:"layout=2006-01-02"`
+		Nick    sql.NullString
+		Count   sql.Null[int32]
+		Deleted sql.NullTime
+	}
+	type rowForm struct {
+		Name    FormInputData
+		Age     FormInputData
+		Score   FormInputData
+		Active  FormInputData
+		Born    FormInputData
+		Nick    FormInputData
+		Count   FormInputData
+		Deleted FormInputData
+	}
+
+	born := time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)
+	d := &row{
+		Name:   sql.NullString{String: "Ada", Valid: true},
+		Age:    sql.NullInt64{Int64: 36, Valid: true},
+		Score:  sql.NullFloat64{Float64: 9.5, Valid: true},
+		Active: sql.NullBool{Bool: false, Valid: true},
+		Born:   sql.NullTime{Time: born, Valid: true},
+		Count:  sql.Null[int32]{V: 7, Valid: true},
+	}
+
+	mapper := NewMapper()
+	f := &rowForm{}
+	if err := mapper.MapToForm(d, nil, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+
+	want := map[string]string{
+		"Name":    "Ada",
+		"Age":     "36",
+		"Score":   "9.5",
+		"Active":  "false",
+		"Born":    "1990-05-17",
+		"Nick":    "",
+		"Count":   "7",
+		"Deleted": "",
+	}
+	got := map[string]string{
+		"Name":    f.Name.Value,
+		"Age":     f.Age.Value,
+		"Score":   f.Score.Value,
+		"Active":  f.Active.Value,
+		"Born":    f.Born.Value,
+		"Nick":    f.Nick.Value,
+		"Count":   f.Count.Value,
+		"Deleted": f.Deleted.Value,
+	}
+	for field, w := range want {
+		if got[field] != w {
+			t.Errorf("%s = %q, want %q", field, got[field], w)
+		}
+	}
+
+	back := &row{Nick: sql.NullString{String: "stale", Valid: true}}
+	if err := mapper.MapFromForm(f, back); err != nil {
+		t.Fatalf("MapFromForm() error = %v", err)
+	}
+	if *back != *d {
+		t.Errorf("MapFromForm() = %+v, want %+v", *back, *d)
+	}
+}
@@ -0,0 +1,199 @@
+package formmap
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// LeafFunc receives one form value produced by MapToFormStream. fieldPath is
+// the path the value's errors are keyed by.
+type LeafFunc func(fieldPath string, input FormInputData) error
+
+// MapToFormStream walks doc, a pointer to a struct or a string-keyed map, and
+// hands every leaf value to emit as soon as it is converted, without building
+// a form struct. Leaves are the values MapToForm would put in a FormInputData:
+// anything with a converter, text or string methods, byte slices and plain
+// scalars. Other structs, slices, arrays and maps are walked, slices element
+// by element ("Tags[0]") and nested maps key by key in sorted order
+// ("Prices[usd]"). Field mappers and field hooks run for each leaf; map hooks
+// don't, as there is no form.
+//
+// An error from emit stops the walk and is returned as is.
+func (m *Mapper) MapToFormStream(doc any, err error, emit LeafFunc) error {
+	valErr, err := asValidationError(err)
+	if err != nil {
+		return err
+	}
+
+	docVal := reflect.ValueOf(doc)
+	if docVal.Kind() != reflect.Ptr && docVal.Kind() != reflect.Map {
+		return fmt.Errorf("%w: doc must be a pointer", ErrNotPointer)
+	}
+	if docVal.IsNil() {
+		return fmt.Errorf("%w: doc cannot be nil", ErrNilInput)
+	}
+	if docVal.Kind() == reflect.Ptr {
+		docVal = docVal.Elem()
+	}
+
+	st := &mapState{valErr: valErr}
+	if err := m.prepareState(st); err != nil {
+		return err
+	}
+
+	s := &streamer{m: m, st: st, emit: emit}
+	root := rootPath(&st.path)
+	switch {
+	case docVal.Kind() == reflect.Struct:
+		err = s.structFields(docVal, root)
+	case docVal.Kind() == reflect.Map && docVal.Type().Key().Kind() == reflect.String:
+		err = s.docMap(docVal, root)
+	default:
+		return fmt.Errorf("%w: cannot stream %s", ErrUnsupportedType, docVal.Type())
+	}
+	if err != nil {
+		return err
+	}
+	return errors.Join(st.errs...)
+}
+
+type streamer struct {
+	m    *Mapper
+	st   *mapState
+	emit LeafFunc
+}
+
+func (s *streamer) structFields(docVal reflect.Value, pathPrefix pathKey) error {
+	for _, plan := range s.m.structPlan(docVal.Type(), nil) {
+		fieldPath, _, fc, skip := s.m.resolvePath(plan.tag, pathPrefix)
+		if skip {
+			continue
+		}
+		if err := s.value(docVal, docVal.Field(plan.index), fieldPath, fc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// docMap walks a map document the way mapDocMap does, with keys as fields.
+func (s *streamer) docMap(docMap reflect.Value, pathPrefix pathKey) error {
+	for _, key := range sortedKeys(docMap) {
+		fieldPath := pathPrefix.field(key.String())
+		fc, _ := s.m.fieldConfig(fieldPath)
+		if fc.Skip {
+			continue
+		}
+		if err := s.value(docMap, docMap.MapIndex(key), fieldPath, fc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *streamer) value(docParent, v reflect.Value, fieldPath pathKey, fc FieldConfig) error {
+	if fieldMapper, siblingMapper := s.m.fieldMappersFor(fieldPath); fieldMapper != nil || siblingMapper != nil {
+		return s.leaf(docParent, v, fieldPath, fc)
+	}
+
+	inner := v
+	for inner.Kind() == reflect.Ptr || inner.Kind() == reflect.Interface {
+		if inner.IsNil() {
+			return s.leaf(docParent, v, fieldPath, fc)
+		}
+		inner = inner.Elem()
+	}
+	if s.isLeaf(inner) {
+		return s.leaf(docParent, v, fieldPath, fc)
+	}
+
+	switch inner.Kind() {
+	case reflect.Struct:
+		return s.structFields(inner, fieldPath)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < inner.Len(); i++ {
+			elemPath := fieldPath.index(i)
+			if err := s.value(docParent, inner.Index(i), elemPath, s.m.elemConfig(fc, elemPath)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		for _, key := range sortedKeys(inner) {
+			keyPath := fieldPath.key(key)
+			if err := s.value(docParent, inner.MapIndex(key), keyPath, s.m.elemConfig(fc, keyPath)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isLeaf reports whether v converts to a single form value rather than being
+// walked.
+func (s *streamer) isLeaf(v reflect.Value) bool {
+	t := v.Type()
+	if conditional, converter, _ := s.m.convertersFor(t); converter != nil || len(conditional) > 0 {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if isSQLNull(t) {
+			return true
+		}
+		for _, iface := range []reflect.Type{textMarshalerType, stringerType} {
+			if t.Implements(iface) || reflect.PointerTo(t).Implements(iface) {
+				return true
+			}
+		}
+		return false
+	case reflect.Slice:
+		return isBytes(t)
+	case reflect.Array, reflect.Map:
+		return false
+	}
+	return true
+}
+
+func (s *streamer) leaf(docParent, v reflect.Value, fieldPath pathKey, fc FieldConfig) error {
+	var input FormInputData
+	formVal := reflect.ValueOf(&input).Elem()
+
+	err := s.m.withFieldHooks(fieldPath, v, formVal, func() error {
+		if handled, err := s.m.applyFieldMapper(s.st, docParent, v, formVal, fieldPath); handled {
+			return err
+		}
+		if err := s.m.mapFormField(docParent, v, formVal, s.st, fieldPath, fc); err != nil {
+			return fieldError(fieldPath.String(), ErrMappingFailed, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return s.st.fail(err)
+	}
+
+	return s.emit(fieldPath.String(), input)
+}
+
+// sortedKeys returns the keys of a map in a stable order.
+func sortedKeys(v reflect.Value) []reflect.Value {
+	keys := v.MapKeys()
+	slices.SortFunc(keys, func(a, b reflect.Value) int {
+		switch {
+		case a.Kind() == reflect.String:
+			return strings.Compare(a.String(), b.String())
+		case a.CanInt():
+			return cmp.Compare(a.Int(), b.Int())
+		case a.CanUint():
+			return cmp.Compare(a.Uint(), b.Uint())
+		case a.CanFloat():
+			return cmp.Compare(a.Float(), b.Float())
+		}
+		return strings.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+	})
+	return keys
+}
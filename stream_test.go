@@ -0,0 +1,94 @@
+package formmap
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMapper_MapToFormStream(t *testing.T) {
+	doc := &TestDocument{
+		Name:      "Widget",
+		Price:     9.5,
+		CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Tags:      []string{"a", "b"},
+		Metadata:  TestMetadata{Version: "1.0"},
+		Items:     []TestItem{{ItemID: "i1", Price: 2}},
+	}
+	valErr := &ValidationError{Errors: Errors{"Items[0].Price": ValidationField{Tag: "required"}}}
+
+	mapper := NewMapper()
+	mapper.RegisterFieldMapper("Metadata.Author", func(docField, formField reflect.Value, fieldPath string, valErr *ValidationError) error {
+		formField.FieldByName("Value").SetString("anonymous")
+		return nil
+	})
+
+	got := map[string]FormInputData{}
+	var order []string
+	err := mapper.MapToFormStream(doc, valErr, func(fieldPath string, input FormInputData) error {
+		got[fieldPath] = input
+		order = append(order, fieldPath)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("MapToFormStream() error = %v", err)
+	}
+
+	want := map[string]FormInputData{
+		"Name":            {Value: "Widget"},
+		"Price":           {Value: "9.5"},
+		"CreatedAt":       {Value: "2024-01-02T03:04:05Z"},
+		"Tags[1]":         {Value: "b"},
+		"Metadata.Author": {Value: "anonymous"},
+		"Items[0].ItemID": {Value: "i1"},
+		"Items[0].Price":  {Value: "2", Error: "This field is required"},
+		"OptionalPtr":     {},
+		"NestedPtr":       {},
+	}
+	for path, w := range want {
+		if g, ok := got[path]; !ok || g != w {
+			t.Errorf("%s = %+v (emitted %v), want %+v", path, g, ok, w)
+		}
+	}
+	if _, ok := got["Metadata"]; ok {
+		t.Error("struct field emitted as a leaf")
+	}
+	if order[0] != "ID" || order[len(order)-1] != "NestedPtr" {
+		t.Errorf("emit order = %v, want document order", order)
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err = mapper.MapToFormStream(doc, nil, func(string, FormInputData) error {
+		calls++
+		return stop
+	})
+	if err != stop || calls != 1 {
+		t.Errorf("MapToFormStream() = %v after %d calls, want the emit error after 1", err, calls)
+	}
+}
+
+func TestMapper_MapToFormStream_MapDocument(t *testing.T) {
+	doc := map[string]any{
+		"name":   "Widget",
+		"prices": map[string]float64{"usd": 9.5, "eur": 8},
+	}
+
+	var paths []string
+	err := NewMapper().MapToFormStream(doc, nil, func(fieldPath string, input FormInputData) error {
+		paths = append(paths, fieldPath+"="+input.Value)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("MapToFormStream() error = %v", err)
+	}
+	if got := strings.Join(paths, " "); got != "name=Widget prices[eur]=8 prices[usd]=9.5" {
+		t.Errorf("emitted %s", got)
+	}
+
+	if err := NewMapper().MapToFormStream(TestDocument{}, nil, nil); !errors.Is(err, ErrNotPointer) {
+		t.Errorf("MapToFormStream(non-pointer) error = %v, want ErrNotPointer", err)
+	}
+}
@@ -1,10 +1,17 @@
 package formmap
 
 import (
+	"encoding/json"
 	"fmt"
+	"maps"
+	"reflect"
+	"slices"
 	"strings"
 )
 
+// GlobalPath keys errors not tied to a field.
+const GlobalPath = "_error"
+
 type Errors map[string]ValidationField
 
 func (e Errors) MsgFor(fieldName string) string {
@@ -12,7 +19,7 @@ func (e Errors) MsgFor(fieldName string) string {
 	if !ok {
 		return ""
 	}
-	return f.Msg()
+	return renderMsg(fieldName, f, "")
 }
 
 func (e Errors) HasError(fieldName string) bool {
@@ -23,13 +30,42 @@ func (e Errors) HasError(fieldName string) bool {
 type ValidationField struct {
 	Tag   string
 	Param string
-	Field string
+	// ParamLabel names the field Param refers to for cross-field tags such as
+	// eqfield, e.g. its json name or label, and is shown in its place.
+	ParamLabel string
+	Field      string
+	// Message replaces the message derived from Tag and Param.
+	Message string
+	// Value is the rejected value and Kind its kind, when the validator
+	// reports them, for message functions and audit logs. They are left out
+	// of JSON, as values may be secrets such as passwords.
+	Value any
+	Kind  reflect.Kind
 }
 
 func (v ValidationField) Msg() string {
+	return v.MsgIn("")
+}
+
+// MsgIn returns the message in locale, from the catalogs registered with
+// RegisterMessages or LoadMessages, falling back to the built-in English one.
+func (v ValidationField) MsgIn(locale string) string {
+	if v.Message != "" {
+		return v.Message
+	}
 	if v.Tag == "" {
 		return ""
 	}
+	if v.ParamLabel != "" {
+		v.Param = v.ParamLabel
+	}
+
+	if template, ok := lookupMessage(locale, v.Tag, pluralForm(locale, v.Param)); ok {
+		return strings.NewReplacer("{param}", v.Param, "{field}", v.Field, "{value}", v.valueString()).Replace(template)
+	}
+	if msg, ok := v.valueMsg(); ok {
+		return msg
+	}
 
 	switch v.Tag {
 	case "required":
@@ -47,18 +83,18 @@ func (v ValidationField) Msg() string {
 	case "lt":
 		return fmt.Sprintf("Value must be less than %s", v.Param)
 	case "min":
-		return fmt.Sprintf("Minimum length is %s", v.Param)
+		return "Must be at least " + v.quantity()
 	case "max":
-		return fmt.Sprintf("Maximum length is %s", v.Param)
+		return "Must be at most " + v.quantity()
 	case "len":
-		return fmt.Sprintf("Length must be exactly %s", v.Param)
+		return "Must be exactly " + v.quantity()
 	case "eq":
 		return fmt.Sprintf("Value must be equal to %s", v.Param)
 	case "ne":
 		return fmt.Sprintf("Value must not be equal to %s", v.Param)
-	case "eqfield":
+	case "eqfield", "eqcsfield":
 		return fmt.Sprintf("This field must match %s", v.Param)
-	case "nefield":
+	case "nefield", "necsfield":
 		return fmt.Sprintf("This field must not match %s", v.Param)
 	case "not_blank":
 		return "This field cannot be empty"
@@ -74,18 +110,84 @@ func (v ValidationField) Msg() string {
 		return "Invalid MongoDB ObjectID"
 	case "uuid":
 		return "Invalid UUID"
+	case "datetime":
+		return fmt.Sprintf("Must be a date in the format %s", v.Param)
+	case "e164":
+		return "Invalid phone number, use the international format, e.g. +14155552671"
+	case "ip":
+		return "Invalid IP address"
+	case "ipv4":
+		return "Invalid IPv4 address"
+	case "ipv6":
+		return "Invalid IPv6 address"
+	case "cidr":
+		return "Invalid CIDR range, e.g. 10.0.0.0/8"
+	case "hostname":
+		return "Invalid hostname"
+	case "fqdn":
+		return "Must be a fully qualified domain name"
+	case "mac":
+		return "Invalid MAC address"
+	case "port":
+		return "Invalid port number"
+	case "base64":
+		return "Must be valid Base64"
+	case "json":
+		return "Must be valid JSON"
+	case "jwt":
+		return "Must be a valid JSON Web Token"
+	case "boolean":
+		return "Must be true or false"
+	case "lowercase":
+		return "Must be lowercase"
+	case "uppercase":
+		return "Must be uppercase"
+	case "ascii":
+		return "Only ASCII characters are allowed"
+	case "printascii":
+		return "Only printable ASCII characters are allowed"
 	case "oneof":
 		return fmt.Sprintf("Must be one of: %s", strings.ReplaceAll(v.Param, " ", ", "))
 	case "gtcsfield", "gtfield":
 		return fmt.Sprintf("Must be greater than %s", v.Param)
 	case "ltcsfield", "ltfield":
 		return fmt.Sprintf("Must be less than %s", v.Param)
+	case "required_if":
+		return "This field is required when " + conditions(v.Param)
+	case "required_unless":
+		return "This field is required unless " + conditions(v.Param)
+	case "required_with":
+		return "This field is required when " + fieldsState(v.Param, "or", "set")
+	case "required_with_all":
+		return "This field is required when " + fieldsState(v.Param, "and", "set")
+	case "required_without":
+		return "This field is required when " + fieldsState(v.Param, "or", "not set")
+	case "required_without_all":
+		return "This field is required when " + fieldsState(v.Param, "and", "not set")
+	case "excluded_if":
+		return "This field must be empty when " + conditions(v.Param)
+	case "excluded_unless":
+		return "This field must be empty unless " + conditions(v.Param)
+	case "excluded_with":
+		return "This field must be empty when " + fieldsState(v.Param, "or", "set")
+	case "excluded_with_all":
+		return "This field must be empty when " + fieldsState(v.Param, "and", "set")
+	case "excluded_without":
+		return "This field must be empty when " + fieldsState(v.Param, "or", "not set")
+	case "excluded_without_all":
+		return "This field must be empty when " + fieldsState(v.Param, "and", "not set")
+	case "gtecsfield", "gtefield":
+		return fmt.Sprintf("Must be at least %s", v.Param)
+	case "ltecsfield", "ltefield":
+		return fmt.Sprintf("Must be at most %s", v.Param)
 	case "contains":
 		return fmt.Sprintf("Must contain '%s'", v.Param)
 	case "startswith":
 		return fmt.Sprintf("Must start with '%s'", v.Param)
 	case "endswith":
 		return fmt.Sprintf("Must end with '%s'", v.Param)
+	case "invalid":
+		return "The submitted data is invalid"
 	default:
 		msg := fmt.Sprintf("Validation failed on '%s' tag", v.Tag)
 		if v.Param != "" {
@@ -95,12 +197,158 @@ func (v ValidationField) Msg() string {
 	}
 }
 
+// valueString formats Value for messages, or returns "" for values that
+// aren't plain strings, numbers or booleans.
+func (v ValidationField) valueString() string {
+	switch v.Kind {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return fmt.Sprint(v.Value)
+	}
+	return ""
+}
+
+// valueMsg returns the message quoting the rejected value, for tags turned on
+// with ShowValues.
+func (v ValidationField) valueMsg() (string, bool) {
+	value := v.valueString()
+	if value == "" || !showsValue(v.Tag) {
+		return "", false
+	}
+
+	isString := v.Kind == reflect.String
+	switch {
+	case v.Tag == "oneof":
+		return fmt.Sprintf("'%s' is not one of: %s", value, strings.ReplaceAll(v.Param, " ", ", ")), true
+	case v.Tag == "datetime":
+		return fmt.Sprintf("'%s' is not a date in the format %s", value, v.Param), true
+	case v.Tag == "min" && isString:
+		return fmt.Sprintf("'%s' is shorter than %s %s", value, v.Param, characters(v.Param)), true
+	case v.Tag == "max" && isString:
+		return fmt.Sprintf("'%s' is longer than %s %s", value, v.Param, characters(v.Param)), true
+	case v.Tag == "len" && isString:
+		return fmt.Sprintf("'%s' is not exactly %s %s", value, v.Param, characters(v.Param)), true
+	case v.Tag == "min":
+		return fmt.Sprintf("%s is less than %s", value, v.Param), true
+	case v.Tag == "max":
+		return fmt.Sprintf("%s is more than %s", value, v.Param), true
+	}
+	return "", false
+}
+
+func characters(param string) string {
+	if pluralForm("en", param) == "one" {
+		return "character"
+	}
+	return "characters"
+}
+
+// quantity renders the param of min, max and len for the kind of value it
+// bounds: "3 characters" for strings, "2 items" for slices, arrays and maps,
+// and the bare number for numbers. Errors of unknown kind count characters.
+func (v ValidationField) quantity() string {
+	switch v.Kind {
+	case reflect.Invalid, reflect.String:
+		return v.Param + " " + characters(v.Param)
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if pluralForm("en", v.Param) == "one" {
+			return v.Param + " item"
+		}
+		return v.Param + " items"
+	}
+	return v.Param
+}
+
+// conditions renders the "Field value" pairs of required_if and friends, e.g.
+// "Status is active and Kind is paid".
+func conditions(param string) string {
+	fields := strings.Fields(param)
+	var parts []string
+	for i := 0; i+1 < len(fields); i += 2 {
+		parts = append(parts, fields[i]+" is "+fields[i+1])
+	}
+	return strings.Join(parts, " and ")
+}
+
+// fieldsState renders the field list of required_with and friends, e.g.
+// "Phone or Email is set" or "Phone and Email are set".
+func fieldsState(param, conj, state string) string {
+	fields := strings.Fields(param)
+	if len(fields) <= 1 {
+		return param + " is " + state
+	}
+	list := strings.Join(fields[:len(fields)-1], ", ") + " " + conj + " " + fields[len(fields)-1]
+	if conj == "and" {
+		return list + " are " + state
+	}
+	return list + " is " + state
+}
+
 func (v ValidationField) String() string {
 	return v.Msg()
 }
 
+// jsonField is the JSON shape of a ValidationField. Decoding only keeps a
+// message that differs from the one derived from the tag.
+type jsonField struct {
+	Tag        string `json:"tag"`
+	Param      string `json:"param"`
+	ParamLabel string `json:"param_label,omitempty"`
+	Field      string `json:"field,omitempty"`
+	Message    string `json:"message"`
+}
+
+func (v ValidationField) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.json(v.Msg()))
+}
+
+func (v ValidationField) json(msg string) jsonField {
+	return jsonField{Tag: v.Tag, Param: v.Param, ParamLabel: v.ParamLabel, Field: v.Field, Message: msg}
+}
+
+func (v *ValidationField) UnmarshalJSON(data []byte) error {
+	var f jsonField
+	if err := json.Unmarshal(data, &f); err != nil {
+		return err
+	}
+	*v = ValidationField{Tag: f.Tag, Param: f.Param, ParamLabel: f.ParamLabel, Field: f.Field}
+	if f.Message != v.Msg() {
+		v.Message = f.Message
+	}
+	return nil
+}
+
 type ValidationError struct {
+	// Errors holds the first failure of each path.
 	Errors Errors
+	// AllErrors holds every failure of a path, in the order reported, for
+	// paths that failed more than once.
+	AllErrors map[string][]ValidationField
+	// Locale selects the message catalog messages are rendered from.
+	Locale string
+
+	// pathType and aliases let lookups use Go and json paths alike; see
+	// IndexPaths.
+	pathType reflect.Type
+	aliases  map[string]string
+}
+
+// FromFlatMap builds a ValidationError from messages keyed by field path, the
+// shape many payment APIs, legacy backends and validation libraries return,
+// so they can be mapped like any other errors. Each message becomes a
+// ValidationField with that Message, the first of a path being its error.
+func FromFlatMap(errs map[string][]string) *ValidationError {
+	v := &ValidationError{Errors: make(Errors)}
+	for path, msgs := range errs {
+		for _, msg := range msgs {
+			if msg != "" {
+				v.AddMsg(path, msg)
+			}
+		}
+	}
+	return v
 }
 
 func (v *ValidationError) Error() string {
@@ -109,21 +357,320 @@ func (v *ValidationError) Error() string {
 	}
 
 	var msgs []string
-	for field, err := range v.Errors {
-		msgs = append(msgs, fmt.Sprintf("%s: %s", field, err.Msg()))
+	for _, field := range v.Fields() {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", field, renderMsg(field, v.Errors[field], v.Locale)))
+	}
+	count := "1 field"
+	if len(msgs) != 1 {
+		count = fmt.Sprintf("%d fields", len(msgs))
+	}
+	return fmt.Sprintf("validation failed (%s): %s", count, strings.Join(msgs, "; "))
+}
+
+// Fields returns the paths with errors in a stable order: by segment, with
+// slice indices compared as numbers, so "Items[2]" comes before "Items[10]".
+func (v *ValidationError) Fields() []string {
+	if v == nil {
+		return nil
+	}
+	return slices.SortedFunc(maps.Keys(v.Errors), comparePaths)
+}
+
+type jsonValidationError struct {
+	Errors    Errors                       `json:"errors"`
+	AllErrors map[string][]ValidationField `json:"all_errors,omitempty"`
+}
+
+// jsonErrors is the encoded shape of a ValidationError, with its messages
+// rendered as MsgFor renders them.
+type jsonErrors struct {
+	Errors    map[string]jsonField   `json:"errors"`
+	AllErrors map[string][]jsonField `json:"all_errors,omitempty"`
+}
+
+// MarshalJSON encodes v as
+//
+//	{"errors": {"Name": {"tag": "required", "param": "", "message": "This field is required"}}}
+//
+// so API handlers can return the errors server-rendered forms display.
+// Messages are rendered in v's Locale.
+func (v ValidationError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.json())
+}
+
+func (v ValidationError) json() jsonErrors {
+	out := jsonErrors{Errors: make(map[string]jsonField, len(v.Errors))}
+	for path, f := range v.Errors {
+		out.Errors[path] = f.json(renderMsg(path, f, v.Locale))
+	}
+	for path, all := range v.AllErrors {
+		if out.AllErrors == nil {
+			out.AllErrors = make(map[string][]jsonField, len(v.AllErrors))
+		}
+		fields := make([]jsonField, len(all))
+		for i, f := range all {
+			fields[i] = f.json(renderMsg(path, f, v.Locale))
+		}
+		out.AllErrors[path] = fields
+	}
+	return out
+}
+
+func (v *ValidationError) UnmarshalJSON(data []byte) error {
+	var decoded jsonValidationError
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	if decoded.Errors == nil {
+		decoded.Errors = make(Errors)
 	}
-	return "validation failed: " + strings.Join(msgs, "; ")
+	v.Errors = decoded.Errors
+	v.AllErrors = decoded.AllErrors
+	return nil
 }
 
 func (v *ValidationError) MsgFor(fieldName string) string {
 	if v == nil {
 		return ""
 	}
-	return v.Errors.MsgFor(fieldName)
+	key, ok := v.key(fieldName)
+	if !ok {
+		return ""
+	}
+	return renderMsg(key, v.Errors[key], v.Locale)
+}
+
+// All returns every failure of path, or nil if it has none.
+func (v *ValidationError) All(path string) []ValidationField {
+	if v == nil {
+		return nil
+	}
+	path, ok := v.key(path)
+	if !ok {
+		return nil
+	}
+	field := v.Errors[path]
+	if all := v.AllErrors[path]; len(all) > 0 {
+		return all
+	}
+	return []ValidationField{field}
+}
+
+// MsgsFor returns the messages of every failure of path.
+func (v *ValidationError) MsgsFor(path string) []string {
+	all := v.All(path)
+	if len(all) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(all))
+	for i, field := range all {
+		msgs[i] = renderMsg(path, field, v.Locale)
+	}
+	return msgs
+}
+
+// msgsAt is MsgsFor, only turning the path into a string for paths with
+// errors.
+func (v *ValidationError) msgsAt(p pathKey) []string {
+	if v == nil {
+		return nil
+	}
+	if _, ok := v.Errors[string(p.bytes())]; !ok {
+		if _, ok := v.aliases[string(p.bytes())]; !ok {
+			return nil
+		}
+	}
+	return v.MsgsFor(p.String())
 }
 
 func (v *ValidationError) HasError(fieldName string) bool {
-	return v != nil && v.Errors.HasError(fieldName)
+	if v == nil {
+		return false
+	}
+	_, ok := v.key(fieldName)
+	return ok
+}
+
+// AnyErrorUnder reports whether prefix or anything below it failed, e.g.
+// "Items", "Items[2]" or "Items[2].Price" for AnyErrorUnder("Items"), so a
+// template can highlight a whole section or tab.
+func (v *ValidationError) AnyErrorUnder(prefix string) bool {
+	if v == nil {
+		return false
+	}
+	if prefix == "" {
+		return len(v.Errors) > 0
+	}
+
+	prefixes := []string{prefix}
+	if v.pathType != nil {
+		prefixes = append(prefixes, convertPath(v.pathType, prefix, true), convertPath(v.pathType, prefix, false))
+	}
+	for path := range v.Errors {
+		for _, p := range prefixes {
+			if rest, ok := strings.CutPrefix(path, p); ok && (rest == "" || rest[0] == '.' || rest[0] == '[') {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Add records a failed validation tag for path, e.g. Add("Email", "unique",
+// ""). If path already has an error, that stays its first and the new one is
+// only added to AllErrors.
+func (v *ValidationError) Add(path, tag, param string) {
+	v.add(path, ValidationField{Tag: tag, Param: param}, false)
+}
+
+// AddMsg records an error with a ready-made message, e.g. AddMsg("Email",
+// "Email already taken"), as Add does.
+func (v *ValidationError) AddMsg(path, message string) {
+	v.add(path, ValidationField{Message: message}, false)
+}
+
+// AddField records field for path as Add does, for validators that fill in
+// more than the tag, such as the rejected Value.
+func (v *ValidationError) AddField(path string, field ValidationField) {
+	v.add(path, field, false)
+}
+
+// Set records the error for path, replacing any existing ones.
+func (v *ValidationError) Set(path string, field ValidationField) {
+	v.add(path, field, true)
+}
+
+// Remove drops the error for path, e.g. once a business rule overrides a
+// struct validation failure.
+func (v *ValidationError) Remove(path string) {
+	path, _ = v.key(path)
+	delete(v.Errors, path)
+	delete(v.AllErrors, path)
+}
+
+func (v *ValidationError) add(path string, field ValidationField, overwrite bool) {
+	if v.Errors == nil {
+		v.Errors = make(Errors)
+	}
+	path, _ = v.key(path)
+	v.indexPath(path)
+	first, exists := v.Errors[path]
+	if !exists || overwrite {
+		v.Errors[path] = field
+		delete(v.AllErrors, path)
+		return
+	}
+
+	if v.AllErrors == nil {
+		v.AllErrors = make(map[string][]ValidationField)
+	}
+	if len(v.AllErrors[path]) == 0 {
+		v.AllErrors[path] = []ValidationField{first}
+	}
+	v.AllErrors[path] = append(v.AllErrors[path], field)
+}
+
+// Merge adds other's errors to v, e.g. business rule or uniqueness failures
+// to those of struct validation. A path failing in both keeps v's errors
+// first, with other's added to AllErrors, unless overwrite is set.
+func (v *ValidationError) Merge(other ValidationError, overwrite bool) {
+	if len(other.Errors) == 0 {
+		return
+	}
+	for path := range other.Errors {
+		for i, field := range other.All(path) {
+			v.add(path, field, overwrite && i == 0)
+		}
+	}
+}
+
+// AddGlobal records an error not tied to a field, e.g. "Your session
+// expired, please resubmit".
+func (v *ValidationError) AddGlobal(message string) {
+	v.AddMsg(GlobalPath, message)
+}
+
+// Globals returns the messages of the errors not tied to a field.
+func (v *ValidationError) Globals() []string {
+	return v.MsgsFor(GlobalPath)
+}
+
+// ErrorsFor returns the errors below prefix with the prefix removed, e.g.
+// "Metadata.Version" becomes "Version" for ErrorsFor("Metadata"), so a
+// sub-form can be mapped on its own. An error on prefix itself is kept under
+// "_error". The result is never nil.
+func (v *ValidationError) ErrorsFor(prefix string) *ValidationError {
+	sub := &ValidationError{Errors: make(Errors)}
+	if v == nil {
+		return sub
+	}
+	sub.Locale = v.Locale
+
+	for path := range v.Errors {
+		rest, ok := strings.CutPrefix(path, prefix+".")
+		if path == prefix {
+			rest, ok = GlobalPath, true
+		}
+		if !ok {
+			continue
+		}
+		for _, field := range v.All(path) {
+			sub.add(rest, field, false)
+		}
+	}
+	return sub
+}
+
+// clone returns a deep copy of v, keeping its Locale and path index, so
+// changes to the copy, such as hooks editing errors, don't reach v.
+func (v *ValidationError) clone() *ValidationError {
+	if v == nil {
+		return nil
+	}
+	out := &ValidationError{
+		Errors:   maps.Clone(v.Errors),
+		Locale:   v.Locale,
+		pathType: v.pathType,
+		aliases:  maps.Clone(v.aliases),
+	}
+	for path, all := range v.AllErrors {
+		if out.AllErrors == nil {
+			out.AllErrors = make(map[string][]ValidationField)
+		}
+		out.AllErrors[path] = slices.Clone(all)
+	}
+	return out
+}
+
+// Translate returns a copy of v with every message rendered in locale and
+// stored in Message, so it survives JSON encoding, e.g. for a localized API
+// response next to the form mapped from v. Fields that already have a
+// Message, such as those from a translator, keep it.
+func (v *ValidationError) Translate(locale string) *ValidationError {
+	out := &ValidationError{Errors: make(Errors), Locale: locale}
+	if v == nil {
+		return out
+	}
+	out.pathType, out.aliases = v.pathType, v.aliases
+
+	render := func(path string, f ValidationField) ValidationField {
+		f.Message = renderMsg(path, f, locale)
+		return f
+	}
+	for path, f := range v.Errors {
+		out.Errors[path] = render(path, f)
+	}
+	for path, all := range v.AllErrors {
+		if out.AllErrors == nil {
+			out.AllErrors = make(map[string][]ValidationField)
+		}
+		translated := make([]ValidationField, len(all))
+		for i, f := range all {
+			translated[i] = render(path, f)
+		}
+		out.AllErrors[path] = translated
+	}
+	return out
 }
 
 func (v *ValidationError) IsEmpty() bool {
@@ -1,6 +1,9 @@
 package formmap
 
 import (
+	"encoding/json"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -23,12 +26,32 @@ func TestValidationField_Msg(t *testing.T) {
 		{
 			name:     "min tag with param",
 			field:    ValidationField{Tag: "min", Param: "5"},
-			expected: "Minimum length is 5",
+			expected: "Must be at least 5 characters",
+		},
+		{
+			name:     "datetime tag with layout",
+			field:    ValidationField{Tag: "datetime", Param: "2006-01-02"},
+			expected: "Must be a date in the format 2006-01-02",
 		},
 		{
 			name:     "max tag with param",
 			field:    ValidationField{Tag: "max", Param: "100"},
-			expected: "Maximum length is 100",
+			expected: "Must be at most 100 characters",
+		},
+		{
+			name:     "min tag on an int",
+			field:    ValidationField{Tag: "min", Param: "18", Kind: reflect.Int},
+			expected: "Must be at least 18",
+		},
+		{
+			name:     "min tag on a slice",
+			field:    ValidationField{Tag: "min", Param: "2", Kind: reflect.Slice},
+			expected: "Must be at least 2 items",
+		},
+		{
+			name:     "len tag on a map",
+			field:    ValidationField{Tag: "len", Param: "1", Kind: reflect.Map},
+			expected: "Must be exactly 1 item",
 		},
 		{
 			name:     "gte tag with param",
@@ -126,7 +149,7 @@ func TestValidationErrors_MsgFor(t *testing.T) {
 		{"name", "This field is required"},
 		{"email", "Invalid email address"},
 		{"age", "Value must be at least 18"},
-		{"password", "Minimum length is 8"},
+		{"password", "Must be at least 8 characters"},
 		{"nonexistent", ""},
 	}
 
@@ -182,7 +205,7 @@ func TestValidationError_Error(t *testing.T) {
 			errors: Errors{
 				"name": ValidationField{Tag: "required"},
 			},
-			contains: []string{"validation failed", "name:", "This field is required"},
+			contains: []string{"validation failed (1 field)", "name:", "This field is required"},
 		},
 		{
 			name: "multiple errors",
@@ -190,7 +213,7 @@ func TestValidationError_Error(t *testing.T) {
 				"name":  ValidationField{Tag: "required"},
 				"email": ValidationField{Tag: "email"},
 			},
-			contains: []string{"validation failed", "name:", "email:", "This field is required", "Invalid email address"},
+			contains: []string{"validation failed (2 fields)", "name:", "email:", "This field is required", "Invalid email address"},
 		},
 	}
 
@@ -284,6 +307,8 @@ func TestValidationField_AllTags(t *testing.T) {
 		"not_blank", "alphanum", "alpha", "numeric", "alphanum_with_underscore",
 		"mongodb", "uuid", "oneof", "gtcsfield", "gtfield", "ltcsfield", "ltfield",
 		"contains", "startswith", "endswith",
+		"datetime", "e164", "ip", "ipv4", "ipv6", "cidr", "hostname", "fqdn", "mac", "port",
+		"base64", "json", "jwt", "boolean", "lowercase", "uppercase", "ascii", "printascii",
 	}
 
 	for _, tag := range tags {
@@ -293,10 +318,40 @@ func TestValidationField_AllTags(t *testing.T) {
 			if msg == "" {
 				t.Errorf("Tag %s should return non-empty message", tag)
 			}
+			if strings.HasPrefix(msg, "Validation failed on") {
+				t.Errorf("Tag %s fell back to the generic message %q", tag, msg)
+			}
 		})
 	}
 }
 
+func TestValidationField_ConditionalTags(t *testing.T) {
+	tests := []struct {
+		field ValidationField
+		want  string
+	}{
+		{ValidationField{Tag: "required_if", Param: "Status active"}, "This field is required when Status is active"},
+		{ValidationField{Tag: "required_if", Param: "Status active Kind paid"}, "This field is required when Status is active and Kind is paid"},
+		{ValidationField{Tag: "required_unless", Param: "Kind free"}, "This field is required unless Kind is free"},
+		{ValidationField{Tag: "required_with", Param: "Phone"}, "This field is required when Phone is set"},
+		{ValidationField{Tag: "required_with", Param: "Phone Fax"}, "This field is required when Phone or Fax is set"},
+		{ValidationField{Tag: "required_with_all", Param: "Street City Zip"}, "This field is required when Street, City and Zip are set"},
+		{ValidationField{Tag: "required_without", Param: "Email"}, "This field is required when Email is not set"},
+		{ValidationField{Tag: "required_without_all", Param: "Email Phone"}, "This field is required when Email and Phone are not set"},
+		{ValidationField{Tag: "excluded_if", Param: "Kind free"}, "This field must be empty when Kind is free"},
+		{ValidationField{Tag: "excluded_unless", Param: "Kind paid"}, "This field must be empty unless Kind is paid"},
+		{ValidationField{Tag: "excluded_with", Param: "Coupon"}, "This field must be empty when Coupon is set"},
+		{ValidationField{Tag: "excluded_with_all", Param: "A B"}, "This field must be empty when A and B are set"},
+		{ValidationField{Tag: "excluded_without", Param: "A B"}, "This field must be empty when A or B is not set"},
+		{ValidationField{Tag: "excluded_without_all", Param: "A B"}, "This field must be empty when A and B are not set"},
+	}
+	for _, tt := range tests {
+		if got := tt.field.Msg(); got != tt.want {
+			t.Errorf("%s %q: Msg() = %q, want %q", tt.field.Tag, tt.field.Param, got, tt.want)
+		}
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && containsSubstring(s, substr))
 }
@@ -309,3 +364,287 @@ func containsSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestValidationError_JSON(t *testing.T) {
+	valErr := &ValidationError{Errors: Errors{
+		"Name":  ValidationField{Tag: "required"},
+		"Price": ValidationField{Tag: "gte", Param: "0", Field: "Price"},
+	}}
+
+	data, err := json.Marshal(valErr)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	want := `{"errors":{"Name":{"tag":"required","param":"","message":"This field is required"},` +
+		`"Price":{"tag":"gte","param":"0","field":"Price","message":"Value must be at least 0"}}}`
+	if string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+
+	var decoded ValidationError
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(decoded.Errors, valErr.Errors) {
+		t.Errorf("Unmarshal() = %+v, want %+v", decoded.Errors, valErr.Errors)
+	}
+
+	data, err = json.Marshal(ValidationError{})
+	if err != nil || string(data) != `{"errors":{}}` {
+		t.Errorf("Marshal(empty) = %s, %v", data, err)
+	}
+	if err := json.Unmarshal([]byte(`{}`), &decoded); err != nil || decoded.Errors == nil || len(decoded.Errors) != 0 {
+		t.Errorf("Unmarshal({}) = %+v, %v; want empty errors", decoded, err)
+	}
+
+	// Messages follow the error's locale, as MsgFor renders them.
+	RegisterMessages("de", map[string]string{"required": "Pflichtfeld"})
+	t.Cleanup(func() {
+		messagesMu.Lock()
+		defer messagesMu.Unlock()
+		delete(catalogs, "de")
+	})
+	data, err = json.Marshal(ValidationError{Errors: Errors{"Name": {Tag: "required"}}, Locale: "de"})
+	if want := `{"errors":{"Name":{"tag":"required","param":"","message":"Pflichtfeld"}}}`; err != nil || string(data) != want {
+		t.Errorf("Marshal(de) = %s, %v; want %s", data, err, want)
+	}
+}
+
+func TestValidationError_Merge(t *testing.T) {
+	structErrs := ValidationError{Errors: Errors{
+		"Name":  ValidationField{Tag: "required"},
+		"Email": ValidationField{Tag: "email"},
+	}}
+	dbErrs := ValidationError{Errors: Errors{
+		"Email": ValidationField{Tag: "unique"},
+		"Slug":  ValidationField{Tag: "unique"},
+	}}
+
+	kept := ValidationError{}
+	kept.Merge(structErrs, false)
+	kept.Merge(dbErrs, false)
+	if len(kept.Errors) != 3 || kept.Errors["Email"].Tag != "email" || kept.Errors["Slug"].Tag != "unique" {
+		t.Errorf("Merge(overwrite=false) = %+v", kept.Errors)
+	}
+
+	replaced := ValidationError{Errors: Errors{"Email": ValidationField{Tag: "email"}}}
+	replaced.Merge(dbErrs, true)
+	if replaced.Errors["Email"].Tag != "unique" {
+		t.Errorf("Merge(overwrite=true) Email = %+v, want unique", replaced.Errors["Email"])
+	}
+
+	if len(dbErrs.Errors) != 2 {
+		t.Errorf("Merge modified its argument: %+v", dbErrs.Errors)
+	}
+}
+
+func TestValidationError_Builders(t *testing.T) {
+	valErr := &ValidationError{}
+	valErr.Add("Name", "required", "")
+	valErr.Add("Name", "min", "3")
+	valErr.AddMsg("Email", "Email already taken")
+	valErr.Add("Age", "gte", "18")
+
+	if got := valErr.MsgFor("Name"); got != "This field is required" {
+		t.Errorf("Name = %q, want the first error kept", got)
+	}
+	if got := valErr.MsgFor("Email"); got != "Email already taken" {
+		t.Errorf("Email = %q, want custom message", got)
+	}
+
+	valErr.Set("Name", ValidationField{Tag: "min", Param: "3"})
+	if got := valErr.MsgFor("Name"); got != "Must be at least 3 characters" {
+		t.Errorf("Name after Set = %q", got)
+	}
+
+	valErr.Remove("Age")
+	if valErr.HasError("Age") {
+		t.Error("Age still has an error after Remove")
+	}
+
+	data, err := json.Marshal(valErr)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var decoded ValidationError
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(decoded.Errors, valErr.Errors) {
+		t.Errorf("JSON round trip = %+v, want %+v", decoded.Errors, valErr.Errors)
+	}
+}
+
+func TestValidationError_ErrorsFor(t *testing.T) {
+	valErr := &ValidationError{Errors: Errors{
+		"Name":                ValidationField{Tag: "required"},
+		"Metadata":            ValidationField{Tag: "required"},
+		"Metadata.Version":    ValidationField{Tag: "semver"},
+		"Metadata.Author.Bio": ValidationField{Tag: "max", Param: "200"},
+		"MetadataExtra":       ValidationField{Tag: "required"},
+		"Items[0].Price":      ValidationField{Tag: "gte", Param: "0"},
+	}}
+
+	got := valErr.ErrorsFor("Metadata")
+	want := Errors{
+		"_error":     ValidationField{Tag: "required"},
+		"Version":    ValidationField{Tag: "semver"},
+		"Author.Bio": ValidationField{Tag: "max", Param: "200"},
+	}
+	if !reflect.DeepEqual(got.Errors, want) {
+		t.Errorf("ErrorsFor(Metadata) = %+v, want %+v", got.Errors, want)
+	}
+
+	if got := valErr.ErrorsFor("Items[0]"); got.MsgFor("Price") != "Value must be at least 0" {
+		t.Errorf("ErrorsFor(Items[0]) = %+v", got.Errors)
+	}
+	if got := (*ValidationError)(nil).ErrorsFor("Metadata"); got == nil || !got.IsEmpty() {
+		t.Errorf("nil.ErrorsFor() = %+v, want empty", got)
+	}
+}
+
+func TestValidationError_Fields(t *testing.T) {
+	valErr := &ValidationError{Errors: Errors{
+		"Name":            ValidationField{Tag: "required"},
+		"Items[10].Price": ValidationField{Tag: "gte", Param: "0"},
+		"Items[2].Price":  ValidationField{Tag: "gte", Param: "0"},
+		"Items[2]":        ValidationField{Tag: "required"},
+		"Email":           ValidationField{Tag: "email"},
+	}}
+
+	want := []string{"Email", "Items[2]", "Items[2].Price", "Items[10].Price", "Name"}
+	for range 5 {
+		if got := valErr.Fields(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("Fields() = %v, want %v", got, want)
+		}
+	}
+
+	wantErr := "validation failed (5 fields): Email: Invalid email address; Items[2]: This field is required; " +
+		"Items[2].Price: Value must be at least 0; Items[10].Price: Value must be at least 0; Name: This field is required"
+	if got := valErr.Error(); got != wantErr {
+		t.Errorf("Error() = %q, want %q", got, wantErr)
+	}
+
+	if got := (*ValidationError)(nil).Fields(); got != nil {
+		t.Errorf("nil.Fields() = %v, want nil", got)
+	}
+}
+
+func TestValidationError_AllErrors(t *testing.T) {
+	valErr := &ValidationError{}
+	valErr.Add("Username", "min", "3")
+	valErr.Add("Username", "alphanum", "")
+	valErr.AddMsg("Username", "Username already taken")
+	valErr.Add("Email", "email", "")
+
+	if got := valErr.MsgFor("Username"); got != "Must be at least 3 characters" {
+		t.Errorf("MsgFor() = %q, want the first error", got)
+	}
+	wantMsgs := []string{"Must be at least 3 characters", "Only alphanumeric characters are allowed", "Username already taken"}
+	if got := valErr.MsgsFor("Username"); !reflect.DeepEqual(got, wantMsgs) {
+		t.Errorf("MsgsFor(Username) = %v, want %v", got, wantMsgs)
+	}
+	if got := valErr.MsgsFor("Email"); !reflect.DeepEqual(got, []string{"Invalid email address"}) {
+		t.Errorf("MsgsFor(Email) = %v", got)
+	}
+	if got := valErr.MsgsFor("Name"); got != nil {
+		t.Errorf("MsgsFor(Name) = %v, want nil", got)
+	}
+
+	sub := (&ValidationError{Errors: Errors{"User.Username": valErr.Errors["Username"]}, AllErrors: map[string][]ValidationField{
+		"User.Username": valErr.All("Username"),
+	}}).ErrorsFor("User")
+	if got := sub.MsgsFor("Username"); !reflect.DeepEqual(got, wantMsgs) {
+		t.Errorf("ErrorsFor().MsgsFor() = %v, want %v", got, wantMsgs)
+	}
+
+	data, err := json.Marshal(valErr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded ValidationError
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if got := decoded.MsgsFor("Username"); !reflect.DeepEqual(got, wantMsgs) {
+		t.Errorf("decoded MsgsFor() = %v, want %v", got, wantMsgs)
+	}
+
+	valErr.Set("Username", ValidationField{Tag: "required"})
+	if got := valErr.All("Username"); len(got) != 1 || got[0].Tag != "required" {
+		t.Errorf("All() after Set = %v, want only the new error", got)
+	}
+
+	// Registered leaves with an Errors field opt in to every message.
+	type listInput struct {
+		Value, Error string
+		Errors       []string
+	}
+	type form struct {
+		Username listInput
+		Email    FormInputData
+	}
+	valErr.Add("Username", "min", "3")
+	mapper := NewMapper()
+	if err := mapper.RegisterFormFieldType(reflect.TypeOf(listInput{})); err != nil {
+		t.Fatal(err)
+	}
+	f := &form{}
+	if err := mapper.MapToForm(&struct{ Username, Email string }{}, valErr, f); err != nil {
+		t.Fatalf("MapToForm() error = %v", err)
+	}
+	if f.Username.Error != "This field is required" || len(f.Username.Errors) != 2 || f.Email.Error != "Invalid email address" {
+		t.Errorf("form = %+v", f)
+	}
+}
+
+func TestValidationError_AnyErrorUnder(t *testing.T) {
+	valErr := &ValidationError{Errors: Errors{
+		"Items[2].Price": ValidationField{Tag: "gte", Param: "0"},
+		"Shipping":       ValidationField{Tag: "required"},
+		"Tags[0]":        ValidationField{Tag: "min", Param: "2"},
+	}}
+
+	tests := map[string]bool{
+		"Items":          true,
+		"Items[2]":       true,
+		"Items[2].Price": true,
+		"Items[1]":       false,
+		"Shipping":       true,
+		"Ship":           false,
+		"Tags":           true,
+		"Metadata":       false,
+		"":               true,
+	}
+	for prefix, want := range tests {
+		if got := valErr.AnyErrorUnder(prefix); got != want {
+			t.Errorf("AnyErrorUnder(%q) = %v, want %v", prefix, got, want)
+		}
+	}
+	if (*ValidationError)(nil).AnyErrorUnder("Items") {
+		t.Error("nil.AnyErrorUnder() = true")
+	}
+}
+
+func TestFromFlatMap(t *testing.T) {
+	valErr := FromFlatMap(map[string][]string{
+		"card_number": {"Card declined", "Card expired"},
+		"Name":        {"Too short"},
+		"Empty":       {},
+	})
+
+	if got := valErr.MsgsFor("card_number"); !reflect.DeepEqual(got, []string{"Card declined", "Card expired"}) {
+		t.Errorf("MsgsFor(card_number) = %v", got)
+	}
+	if valErr.HasError("Empty") {
+		t.Error("HasError(Empty) = true, want paths without messages left out")
+	}
+
+	form := &struct{ Name FormInputData }{}
+	if err := NewMapper().MapToForm(&struct{ Name string }{}, valErr, form); err != nil {
+		t.Fatal(err)
+	}
+	if form.Name.Error != "Too short" {
+		t.Errorf("Name.Error = %q, want Too short", form.Name.Error)
+	}
+}
@@ -0,0 +1,15 @@
+package formmap
+
+// Validator validates a document and returns its field errors, or nil when
+// it is valid. The playground package wraps go-playground/validator; others
+// can wrap a different library or hand-written rules.
+type Validator interface {
+	Validate(input any) *ValidationError
+}
+
+// ValidatorFunc adapts a function to Validator.
+type ValidatorFunc func(input any) *ValidationError
+
+func (f ValidatorFunc) Validate(input any) *ValidationError {
+	return f(input)
+}
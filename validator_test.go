@@ -0,0 +1,21 @@
+package formmap
+
+import "testing"
+
+func TestValidatorFunc(t *testing.T) {
+	type signup struct{ Email string }
+
+	var v Validator = ValidatorFunc(func(input any) *ValidationError {
+		if input.(*signup).Email == "" {
+			return FromFlatMap(map[string][]string{"Email": {"Enter your email"}})
+		}
+		return nil
+	})
+
+	if valErr := v.Validate(&signup{}); valErr.MsgFor("Email") != "Enter your email" {
+		t.Errorf("Validate() = %v", valErr)
+	}
+	if valErr := v.Validate(&signup{Email: "a@b.c"}); valErr != nil {
+		t.Errorf("Validate() = %v, want nil", valErr)
+	}
+}